@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
+	"time"
 )
 
 // CurrentProgress struct
@@ -14,17 +16,26 @@ type CurrentProgress struct {
 	TotalSize       int64
 }
 
-var concurrencyLimit chan struct{} = make(chan struct{}, 3*runtime.GOMAXPROCS(0))
+var defaultConcurrencyLimit chan struct{} = make(chan struct{}, 3*runtime.GOMAXPROCS(0))
 
 // ShouldDirBeIgnored whether path should be ignored
 type ShouldDirBeIgnored func(path string) bool
 
+// ShouldFileBeIgnored whether path of file (not dir) should be ignored
+type ShouldFileBeIgnored func(path string) bool
+
 // Analyzer is type for dir analyzing function
 type Analyzer interface {
 	AnalyzeDir(path string, ignore ShouldDirBeIgnored) *Dir
 	GetProgressChan() chan CurrentProgress
 	GetDoneChan() chan struct{}
 	ResetProgress()
+	SetMaxConcurrency(n int)
+	SetFollowSymlinks(follow bool)
+	SetDedupHardlinks(dedup bool)
+	SetCrossFilesystems(cross bool)
+	SetReadRetries(retries int, backoff time.Duration, retried ReadRetried, cancel <-chan struct{})
+	SetIgnoreFile(ignore ShouldFileBeIgnored)
 }
 
 // ParallelAnalyzer implements Analyzer
@@ -35,11 +46,33 @@ type ParallelAnalyzer struct {
 	doneChan        chan struct{}
 	wait            *WaitGroup
 	ignoreDir       ShouldDirBeIgnored
+	ignoreFile      ShouldFileBeIgnored
+	concurrency     chan struct{}
+	followSymlinks  bool
+	dedupHardlinks  bool
+	seenLinks       map[fileID]struct{}
+	seenLinksMutex  sync.Mutex
+	noCross         bool
+	rootDevice      uint64
+	getDevice       func(path string) (uint64, bool)
+	stat            func(path string) (os.FileInfo, error)
+	readDir         func(path string) ([]os.DirEntry, error)
+	readRetries     int
+	retryBackoff    time.Duration
+	readRetried     ReadRetried
+	retryCancel     <-chan struct{}
+	sleep           func(d time.Duration, cancel <-chan struct{}) bool
+}
+
+// fileID identifies file (or dir) by its device and inode
+type fileID struct {
+	dev uint64
+	ino uint64
 }
 
 // CreateAnalyzer returns Analyzer
 func CreateAnalyzer() Analyzer {
-	return &ParallelAnalyzer{
+	a := &ParallelAnalyzer{
 		progress: &CurrentProgress{
 			ItemCount: 0,
 			TotalSize: int64(0),
@@ -48,7 +81,13 @@ func CreateAnalyzer() Analyzer {
 		progressOutChan: make(chan CurrentProgress, 1),
 		doneChan:        make(chan struct{}, 1),
 		wait:            (&WaitGroup{}).Init(),
+		concurrency:     defaultConcurrencyLimit,
+		stat:            os.Stat,
+		readDir:         os.ReadDir,
+		sleep:           sleep,
 	}
+	a.getDevice = a.statDevice
+	return a
 }
 
 // GetProgressChan returns channel for getting progress
@@ -68,12 +107,70 @@ func (a *ParallelAnalyzer) ResetProgress() {
 	a.progress.CurrentItemName = ""
 }
 
+// SetIgnoreFile sets function deciding which files should be ignored, nil means no files are ignored.
+// Dirs are ignored by the function given to AnalyzeDir.
+func (a *ParallelAnalyzer) SetIgnoreFile(ignore ShouldFileBeIgnored) {
+	a.ignoreFile = ignore
+}
+
+// SetMaxConcurrency sets maximal number of dirs being read concurrently,
+// 0 means the default limit (3 times GOMAXPROCS)
+func (a *ParallelAnalyzer) SetMaxConcurrency(n int) {
+	if n > 0 {
+		a.concurrency = make(chan struct{}, n)
+	} else {
+		a.concurrency = defaultConcurrencyLimit
+	}
+}
+
+// SetFollowSymlinks sets whether symlinks should be followed.
+// Symlinks pointing to a parent dir are not followed to avoid cycles,
+// but a dir linked multiple times is counted multiple times.
+func (a *ParallelAnalyzer) SetFollowSymlinks(follow bool) {
+	a.followSymlinks = follow
+}
+
+// SetDedupHardlinks sets whether size of hardlinked file should be counted
+// only for the first found link (others are shown with zero size).
+// Links are identified by both device and inode.
+func (a *ParallelAnalyzer) SetDedupHardlinks(dedup bool) {
+	a.dedupHardlinks = dedup
+}
+
+// SetCrossFilesystems sets whether dirs on other filesystems (devices) than
+// the analyzed path should be analyzed too (true by default)
+func (a *ParallelAnalyzer) SetCrossFilesystems(cross bool) {
+	a.noCross = !cross
+}
+
 // AnalyzeDir analyzes given path
 func (a *ParallelAnalyzer) AnalyzeDir(path string, ignore ShouldDirBeIgnored) *Dir {
 	a.ignoreDir = ignore
+	a.seenLinks = make(map[fileID]struct{})
+
+	var (
+		ancestors []fileID
+		info      os.FileInfo
+	)
+	if a.followSymlinks {
+		info, _ = a.stat(path)
+		if info != nil {
+			if id, ok := getFileID(info); ok {
+				ancestors = []fileID{id}
+			}
+		}
+	}
+
+	stayOnDevice := false
+	if a.noCross {
+		a.rootDevice, stayOnDevice = a.getDeviceOf(path, info)
+	}
 
 	go a.updateProgress()
-	dir := a.processDir(path)
+
+	a.concurrency <- struct{}{}
+	dir := a.processDir(path, ancestors, stayOnDevice)
+	<-a.concurrency
 
 	dir.BasePath = filepath.Dir(path)
 	a.wait.Wait()
@@ -87,7 +184,7 @@ func (a *ParallelAnalyzer) AnalyzeDir(path string, ignore ShouldDirBeIgnored) *D
 	return dir
 }
 
-func (a *ParallelAnalyzer) processDir(path string) *Dir {
+func (a *ParallelAnalyzer) processDir(path string, ancestors []fileID, stayOnDevice bool) *Dir {
 	var (
 		file       *File
 		err        error
@@ -99,7 +196,7 @@ func (a *ParallelAnalyzer) processDir(path string) *Dir {
 
 	a.wait.Add(1)
 
-	files, err := os.ReadDir(path)
+	files, err := a.readDirWithRetries(path)
 	if err != nil {
 		log.Print(err.Error())
 	}
@@ -115,35 +212,51 @@ func (a *ParallelAnalyzer) processDir(path string) *Dir {
 
 	for _, f := range files {
 		entryPath := filepath.Join(path, f.Name())
-		if f.IsDir() {
+		subdirAncestors, target, isDir := a.getSubdirAncestors(f, entryPath, ancestors)
+		if isDir {
 			if a.ignoreDir(entryPath) {
 				continue
 			}
+			if stayOnDevice && a.isOnOtherDevice(entryPath, target) {
+				continue
+			}
 			dirCount += 1
 
 			go func(entryPath string) {
-				concurrencyLimit <- struct{}{}
-				subdir := a.processDir(entryPath)
+				a.concurrency <- struct{}{}
+				subdir := a.processDir(entryPath, subdirAncestors, stayOnDevice)
+				// released before the result is sent, so that no goroutine uses the limit
+				// once the analysis is done and the limit can be replaced for the next one
+				<-a.concurrency
 				subdir.Parent = dir
 
 				subDirChan <- subdir
-				<-concurrencyLimit
 			}(entryPath)
 		} else {
-			info, err = f.Info()
-			if err != nil {
-				log.Print(err.Error())
+			if a.ignoreFile != nil && a.ignoreFile(entryPath) {
 				continue
 			}
+
+			// followed symlinks get info of their targets
+			info = target
+			if info == nil {
+				info, err = f.Info()
+				if err != nil {
+					log.Print(err.Error())
+					continue
+				}
+			}
 			file = &File{
 				Name:   f.Name(),
 				Flag:   getFlag(info),
 				Size:   info.Size(),
+				Mtime:  info.ModTime(),
 				Parent: dir,
 			}
 			setPlatformSpecificAttrs(file, info)
+			a.dedupHardlink(file, info)
 
-			totalSize += info.Size()
+			totalSize += file.Size
 
 			dir.Files.Append(file)
 		}
@@ -164,6 +277,108 @@ func (a *ParallelAnalyzer) processDir(path string) *Dir {
 	return dir
 }
 
+func (a *ParallelAnalyzer) statDevice(path string) (uint64, bool) {
+	info, err := a.stat(path)
+	if err != nil {
+		return 0, false
+	}
+	id, ok := getFileID(info)
+	return id.dev, ok
+}
+
+// getDeviceOf returns device of the path, the info is used if given so that the path is not stat'ed again
+func (a *ParallelAnalyzer) getDeviceOf(path string, info os.FileInfo) (uint64, bool) {
+	if info != nil {
+		if id, ok := getFileID(info); ok {
+			return id.dev, true
+		}
+	}
+	return a.getDevice(path)
+}
+
+// isOnOtherDevice returns whether the dir is on other device than the analyzed path,
+// target is info of the followed symlink (nil for dirs)
+func (a *ParallelAnalyzer) isOnOtherDevice(path string, target os.FileInfo) bool {
+	dev, ok := a.getDeviceOf(path, target)
+	return ok && dev != a.rootDevice
+}
+
+// dedupHardlink sets zero size to all links of the file except the first one found
+func (a *ParallelAnalyzer) dedupHardlink(file *File, info os.FileInfo) {
+	if !a.dedupHardlinks || file.Mli == 0 {
+		return
+	}
+	id, ok := getFileID(info)
+	if !ok {
+		return
+	}
+
+	// links are already deduplicated here, no need to do it once more when counting stats
+	file.Mli = 0
+
+	a.seenLinksMutex.Lock()
+	defer a.seenLinksMutex.Unlock()
+
+	if _, seen := a.seenLinks[id]; seen {
+		file.Size = 0
+		file.Usage = 0
+		file.Flag = 'H'
+		return
+	}
+	a.seenLinks[id] = struct{}{}
+}
+
+// getSubdirAncestors returns whether the entry should be analyzed as a dir
+// and the list of dirs above its content (used for symlink cycle detection).
+// Info of the target is returned for followed symlinks so that the target is stat'ed only once.
+func (a *ParallelAnalyzer) getSubdirAncestors(
+	f os.DirEntry, path string, ancestors []fileID,
+) ([]fileID, os.FileInfo, bool) {
+	if !a.followSymlinks {
+		return nil, nil, f.IsDir()
+	}
+
+	var (
+		info   os.FileInfo
+		target os.FileInfo
+		err    error
+	)
+	switch {
+	case f.IsDir():
+		info, err = f.Info()
+	case f.Type()&os.ModeSymlink != 0:
+		info, err = a.stat(path)
+		if err != nil {
+			return nil, nil, false
+		}
+		if !info.IsDir() {
+			return nil, info, false
+		}
+		target = info
+	default:
+		return nil, nil, false
+	}
+	if err != nil {
+		return ancestors, nil, true
+	}
+
+	id, ok := getFileID(info)
+	if !ok {
+		// cycles cannot be detected, symlinked dirs are not followed
+		return nil, nil, f.IsDir()
+	}
+	for _, ancestor := range ancestors {
+		if ancestor == id {
+			log.Printf("skipping symlink cycle: %s", path)
+			return nil, nil, false
+		}
+	}
+
+	res := make([]fileID, len(ancestors), len(ancestors)+1)
+	copy(res, ancestors)
+	return append(res, id), target, true
+}
+
 func (a *ParallelAnalyzer) updateProgress() {
 	for {
 		select {
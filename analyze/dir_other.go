@@ -7,3 +7,7 @@ import (
 )
 
 func setPlatformSpecificAttrs(file *File, f os.FileInfo) {}
+
+func getFileID(f os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
@@ -2,8 +2,13 @@ package analyze
 
 import (
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/dundee/gdu/v4/internal/testdir"
 	"github.com/stretchr/testify/assert"
@@ -55,6 +60,86 @@ func TestIgnoreDir(t *testing.T) {
 	assert.Equal(t, 1, dir.ItemCount)
 }
 
+func TestIgnoreFile(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	var (
+		ignoredDirs []string
+		mutex       sync.Mutex
+	)
+
+	analyzer := CreateAnalyzer()
+	analyzer.SetIgnoreFile(func(path string) bool {
+		return path == "test_dir/nested/file2"
+	})
+	dir := analyzer.AnalyzeDir("test_dir", func(path string) bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		ignoredDirs = append(ignoredDirs, path)
+		return false
+	})
+
+	assert.Equal(t, 4, dir.ItemCount)
+	assert.Equal(t, int64(4096*3+5), dir.Size)
+	// files are not passed to the dir callback
+	assert.ElementsMatch(t, []string{"test_dir/nested", "test_dir/nested/subnested"}, ignoredDirs)
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	for i := 0; i < 20; i++ {
+		path := filepath.Join("test_dir", "dir"+strconv.Itoa(i))
+		os.Mkdir(path, os.ModePerm)
+		os.WriteFile(filepath.Join(path, "file"), []byte("x"), 0644)
+	}
+
+	var (
+		running int32
+		maxSeen int32
+	)
+	ignore := func(_ string) bool {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			seen := atomic.LoadInt32(&maxSeen)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return false
+	}
+
+	analyzer := CreateAnalyzer()
+	analyzer.SetMaxConcurrency(2)
+	dir := analyzer.AnalyzeDir("test_dir", ignore)
+
+	assert.Equal(t, 5+40, dir.ItemCount)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxSeen), int32(2))
+}
+
+func TestMaxConcurrencyOfRepeatedAnalysis(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	for i := 0; i < 20; i++ {
+		os.Mkdir(filepath.Join("test_dir", "dir"+strconv.Itoa(i)), os.ModePerm)
+	}
+
+	analyzer := CreateAnalyzer()
+	for i := 1; i <= 3; i++ {
+		// the limit is replaced while goroutines of the previous analysis must not be using it
+		analyzer.SetMaxConcurrency(i)
+		dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+		<-analyzer.GetDoneChan()
+
+		assert.Equal(t, 5+20, dir.ItemCount)
+	}
+}
+
 func TestFlags(t *testing.T) {
 	fin := testdir.CreateTestDir()
 	defer fin()
@@ -79,6 +164,176 @@ func TestFlags(t *testing.T) {
 	assert.Equal(t, 'e', dir.Files[1].GetFlag())
 }
 
+func TestDedupHardlinks(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.Link("test_dir/nested/file2", "test_dir/nested/file3")
+	os.Link("test_dir/nested/file2", "test_dir/nested/subnested/file4")
+
+	analyzer := CreateAnalyzer()
+	analyzer.SetDedupHardlinks(true)
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+
+	findByName := func(files Files, name string) Item {
+		i, _ := files.FindByName(name)
+		return files[i]
+	}
+
+	nested := dir.Files[0].(*Dir)
+	subnested := findByName(nested.Files, "subnested").(*Dir)
+
+	links := []Item{
+		findByName(nested.Files, "file2"),
+		findByName(nested.Files, "file3"),
+		findByName(subnested.Files, "file4"),
+	}
+
+	var naiveSum, dedupSum int64
+	counted := 0
+	for _, link := range links {
+		naiveSum += 2
+		dedupSum += link.GetSize()
+		if link.GetSize() > 0 {
+			counted++
+		} else {
+			assert.Equal(t, 'H', link.GetFlag())
+		}
+	}
+
+	assert.Equal(t, 1, counted)
+	assert.Less(t, dedupSum, naiveSum)
+	assert.Equal(t, int64(7+4096*3), dir.Size)
+	assert.Equal(t, 7, dir.ItemCount)
+}
+
+func TestNoCrossFilesystems(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	analyzer := CreateAnalyzer().(*ParallelAnalyzer)
+	analyzer.SetCrossFilesystems(false)
+	analyzer.getDevice = func(path string) (uint64, bool) {
+		if path == "test_dir/nested/subnested" {
+			return 2, true // simulated mount point
+		}
+		return 1, true
+	}
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+
+	assert.Equal(t, 3, dir.ItemCount)
+	_, found := dir.Files[0].(*Dir).Files.FindByName("subnested")
+	assert.False(t, found)
+}
+
+func TestCrossFilesystems(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	analyzer := CreateAnalyzer().(*ParallelAnalyzer)
+	analyzer.getDevice = func(path string) (uint64, bool) {
+		if path == "test_dir/nested/subnested" {
+			return 2, true
+		}
+		return 1, true
+	}
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+
+	assert.Equal(t, 5, dir.ItemCount)
+}
+
+func TestSymlinkTargetsStatedOnce(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.Symlink("file2", "test_dir/nested/link")
+	os.Symlink("subnested", "test_dir/nested/dirlink")
+
+	var mutex sync.Mutex
+	calls := make(map[string]int)
+
+	analyzer := CreateAnalyzer().(*ParallelAnalyzer)
+	analyzer.SetFollowSymlinks(true)
+	analyzer.SetCrossFilesystems(false)
+	analyzer.stat = func(path string) (os.FileInfo, error) {
+		mutex.Lock()
+		calls[path]++
+		mutex.Unlock()
+		return os.Stat(path)
+	}
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+
+	nested := dir.Files[0].(*Dir)
+	i, found := nested.Files.FindByName("link")
+	assert.True(t, found)
+	assert.Equal(t, int64(2), nested.Files[i].GetSize())
+
+	for path, count := range calls {
+		assert.Equal(t, 1, count, path)
+	}
+	assert.Contains(t, calls, "test_dir")
+	assert.Contains(t, calls, "test_dir/nested/link")
+}
+
+func TestFollowSymlinks(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.Symlink("nested", "test_dir/link")
+	os.Symlink("nested/file2", "test_dir/file3")
+
+	analyzer := CreateAnalyzer()
+	analyzer.SetFollowSymlinks(true)
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+	sort.Sort(dir.Files)
+
+	assert.Equal(t, 5+4+1, dir.ItemCount)
+
+	// link to dir
+	assert.Equal(t, "link", dir.Files[0].GetName())
+	assert.True(t, dir.Files[0].IsDir())
+	assert.Equal(t, 4, dir.Files[0].GetItemCount())
+
+	// link to file
+	assert.Equal(t, "file3", dir.Files[2].GetName())
+	assert.Equal(t, int64(2), dir.Files[2].GetSize())
+	assert.Equal(t, ' ', dir.Files[2].GetFlag())
+}
+
+func TestFollowSymlinksWithLoop(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.Symlink("..", "test_dir/nested/parent")
+	os.Symlink(".", "test_dir/nested/subnested/self")
+
+	var dir *Dir
+	done := make(chan struct{})
+	go func() {
+		analyzer := CreateAnalyzer()
+		analyzer.SetFollowSymlinks(true)
+		dir = analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("analysis of dir with symlink loop did not finish")
+	}
+
+	// symlinks creating loop are counted as plain symlinks
+	assert.Equal(t, 5+2, dir.ItemCount)
+
+	nested := dir.Files[0].(*Dir)
+	for _, item := range nested.Files {
+		if item.GetName() == "parent" {
+			assert.False(t, item.IsDir())
+			assert.Equal(t, '@', item.GetFlag())
+		}
+	}
+}
+
 func TestHardlink(t *testing.T) {
 	fin := testdir.CreateTestDir()
 	defer fin()
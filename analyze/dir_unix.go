@@ -20,3 +20,11 @@ func setPlatformSpecificAttrs(file *File, f os.FileInfo) {
 		}
 	}
 }
+
+func getFileID(f os.FileInfo) (fileID, bool) {
+	switch stat := f.Sys().(type) {
+	case *syscall.Stat_t:
+		return fileID{dev: uint64(stat.Dev), ino: stat.Ino}, true
+	}
+	return fileID{}, false
+}
@@ -3,6 +3,7 @@ package analyze
 import (
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // AlreadyCountedHardlinks holds all files with hardlinks that have already been counted
@@ -17,6 +18,7 @@ type Item interface {
 	GetSize() int64
 	GetUsage() int64
 	GetItemCount() int
+	GetMtime() time.Time
 	GetParent() *Dir
 	getItemStats(links AlreadyCountedHardlinks) (int, int64, int64)
 }
@@ -28,6 +30,7 @@ type File struct {
 	Size   int64
 	Usage  int64
 	Mli    uint64 // MutliLinkInode - Inode number of file with multiple links (hard link)
+	Mtime  time.Time
 	Parent *Dir
 }
 
@@ -66,6 +69,11 @@ func (f *File) GetItemCount() int {
 	return 1
 }
 
+// GetMtime returns time of last modification of the file
+func (f *File) GetMtime() time.Time {
+	return f.Mtime
+}
+
 func (f *File) alreadyCounted(links AlreadyCountedHardlinks) bool {
 	mli := f.Mli
 	if mli > 0 {
@@ -123,7 +131,7 @@ func (f *Dir) getItemStats(links AlreadyCountedHardlinks) (int, int64, int64) {
 	return f.ItemCount, f.GetSize(), f.GetUsage()
 }
 
-// UpdateStats recursively updates size and item count
+// UpdateStats recursively updates size, item count and mtime (set to the latest mtime of contained items)
 func (f *Dir) UpdateStats(links AlreadyCountedHardlinks) {
 	totalSize := int64(4096)
 	totalUsage := int64(4096)
@@ -134,6 +142,10 @@ func (f *Dir) UpdateStats(links AlreadyCountedHardlinks) {
 		totalUsage += usage
 		itemCount += count
 
+		if entry.GetMtime().After(f.Mtime) {
+			f.Mtime = entry.GetMtime()
+		}
+
 		switch entry.GetFlag() {
 		case '!', '.':
 			if f.Flag != '!' {
@@ -219,6 +231,13 @@ func (f ByName) Len() int           { return len(f) }
 func (f ByName) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
 func (f ByName) Less(i, j int) bool { return f[i].GetName() > f[j].GetName() }
 
+// ByMtime sorts files by time of last modification
+type ByMtime Files
+
+func (f ByMtime) Len() int           { return len(f) }
+func (f ByMtime) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f ByMtime) Less(i, j int) bool { return f[i].GetMtime().After(f[j].GetMtime()) }
+
 // RemoveItemFromDir removes item from dir
 func RemoveItemFromDir(dir *Dir, item Item) error {
 	err := os.RemoveAll(item.GetPath())
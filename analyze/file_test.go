@@ -3,6 +3,7 @@ package analyze
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/dundee/gdu/v4/internal/testdir"
 	"github.com/stretchr/testify/assert"
@@ -263,3 +264,20 @@ func TestUpdateStats(t *testing.T) {
 
 	assert.Equal(t, int64(4096+5), dir.Size)
 }
+
+func TestUpdateStatsMtime(t *testing.T) {
+	now := time.Now()
+	dir := Dir{
+		File: &File{
+			Name: "xxx",
+		},
+	}
+	dir.Files = Files{
+		&File{Name: "yyy", Mtime: now.Add(-time.Hour), Parent: &dir},
+		&File{Name: "zzz", Mtime: now, Parent: &dir},
+	}
+
+	dir.UpdateStats(nil)
+
+	assert.Equal(t, now, dir.GetMtime())
+}
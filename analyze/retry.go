@@ -0,0 +1,66 @@
+package analyze
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+)
+
+// ReadRetried is called before each retry of a failed read of the dir
+type ReadRetried func(path string, attempt int, err error)
+
+// SetReadRetries sets how many times reading of a dir failed with a transient error (e.g. EINTR, ETIMEDOUT)
+// is retried before the dir is marked as unreadable. Delay before the first retry is backoff,
+// it is doubled before each next one. retried (if not nil) is called before each retry.
+// No more retries are made and the delay is interrupted once cancel is closed (nil never is).
+func (a *ParallelAnalyzer) SetReadRetries(
+	retries int, backoff time.Duration, retried ReadRetried, cancel <-chan struct{},
+) {
+	a.readRetries = retries
+	a.retryBackoff = backoff
+	a.readRetried = retried
+	a.retryCancel = cancel
+}
+
+func isTransientError(err error) bool {
+	for _, transient := range transientErrors {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+	return false
+}
+
+// readDirWithRetries reads the dir, transient errors are retried with exponential backoff
+func (a *ParallelAnalyzer) readDirWithRetries(path string) ([]os.DirEntry, error) {
+	files, err := a.readDir(path)
+
+	delay := a.retryBackoff
+	for attempt := 1; err != nil && attempt <= a.readRetries && isTransientError(err); attempt++ {
+		log.Printf("retrying read of %s (attempt %d of %d): %s", path, attempt, a.readRetries, err.Error())
+		if a.readRetried != nil {
+			a.readRetried(path, attempt, err)
+		}
+		if !a.sleep(delay, a.retryCancel) {
+			break
+		}
+		delay *= 2
+
+		files, err = a.readDir(path)
+	}
+	return files, err
+}
+
+// sleep waits for the given duration, returns false if cancel was closed before
+func sleep(d time.Duration, cancel <-chan struct{}) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-cancel:
+		return false
+	}
+}
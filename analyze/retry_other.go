@@ -0,0 +1,10 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package analyze
+
+import "syscall"
+
+// transientErrors are errors after which reading of the dir can succeed when retried,
+// EAGAIN is not defined on plan9
+var transientErrors = []error{syscall.EINTR, syscall.ETIMEDOUT}
@@ -0,0 +1,130 @@
+package analyze
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyReadDir returns readDir failing with given error the first n times the path is read
+func flakyReadDir(failingPath string, n int, err error) (func(string) ([]os.DirEntry, error), func() int) {
+	var (
+		mutex sync.Mutex
+		calls int
+	)
+	readDir := func(path string) ([]os.DirEntry, error) {
+		if path == failingPath {
+			mutex.Lock()
+			defer mutex.Unlock()
+			calls++
+			if calls <= n {
+				return nil, &fs.PathError{Op: "readdirent", Path: path, Err: err}
+			}
+		}
+		return os.ReadDir(path)
+	}
+	getCalls := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return calls
+	}
+	return readDir, getCalls
+}
+
+func TestReadRetries(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	var (
+		delays  []time.Duration
+		retried []int
+	)
+
+	analyzer := CreateAnalyzer().(*ParallelAnalyzer)
+	readDir, calls := flakyReadDir("test_dir/nested", 2, syscall.ETIMEDOUT)
+	analyzer.readDir = readDir
+	analyzer.sleep = func(d time.Duration, cancel <-chan struct{}) bool {
+		delays = append(delays, d)
+		return true
+	}
+	analyzer.SetReadRetries(3, 10*time.Millisecond, func(path string, attempt int, err error) {
+		assert.Equal(t, "test_dir/nested", path)
+		assert.True(t, errors.Is(err, syscall.ETIMEDOUT))
+		retried = append(retried, attempt)
+	}, nil)
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+
+	assert.Equal(t, 3, calls())
+	assert.Equal(t, []int{1, 2}, retried)
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, delays)
+	assert.Equal(t, 5, dir.ItemCount)
+	assert.Equal(t, ' ', dir.Files[0].GetFlag())
+}
+
+func TestReadRetriesExhausted(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	analyzer := CreateAnalyzer().(*ParallelAnalyzer)
+	readDir, calls := flakyReadDir("test_dir/nested", 5, syscall.EINTR)
+	analyzer.readDir = readDir
+	analyzer.sleep = func(d time.Duration, cancel <-chan struct{}) bool { return true }
+	analyzer.SetReadRetries(2, time.Millisecond, nil, nil)
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+
+	assert.Equal(t, 3, calls())
+	assert.Equal(t, '!', dir.Files[0].GetFlag())
+}
+
+func TestReadRetriesOfPermanentError(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	analyzer := CreateAnalyzer().(*ParallelAnalyzer)
+	readDir, calls := flakyReadDir("test_dir/nested", 1, syscall.EACCES)
+	analyzer.readDir = readDir
+	analyzer.sleep = func(d time.Duration, cancel <-chan struct{}) bool { return true }
+	analyzer.SetReadRetries(3, time.Millisecond, nil, nil)
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+
+	assert.Equal(t, 1, calls())
+	assert.Equal(t, '!', dir.Files[0].GetFlag())
+}
+
+func TestNoReadRetriesByDefault(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	analyzer := CreateAnalyzer().(*ParallelAnalyzer)
+	readDir, calls := flakyReadDir("test_dir/nested", 1, syscall.EINTR)
+	analyzer.readDir = readDir
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+
+	assert.Equal(t, 1, calls())
+	assert.Equal(t, '!', dir.Files[0].GetFlag())
+}
+
+func TestReadRetriesCancelled(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	cancel := make(chan struct{})
+	close(cancel)
+
+	analyzer := CreateAnalyzer().(*ParallelAnalyzer)
+	readDir, calls := flakyReadDir("test_dir/nested", 5, syscall.EINTR)
+	analyzer.readDir = readDir
+	analyzer.SetReadRetries(3, time.Hour, nil, cancel)
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+
+	// the delay is interrupted and the read is not retried
+	assert.Equal(t, 1, calls())
+	assert.Equal(t, '!', dir.Files[0].GetFlag())
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package analyze
+
+import "syscall"
+
+// transientErrors are errors after which reading of the dir can succeed when retried
+// (e.g. on NFS or SMB mounts)
+var transientErrors = []error{syscall.EINTR, syscall.EAGAIN, syscall.ETIMEDOUT}
@@ -0,0 +1,109 @@
+package analyze
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// scanVersion is increased when the format of saved scan changes
+const scanVersion = 1
+
+type scanFile struct {
+	Version  int
+	BasePath string
+	Root     *scanItem
+}
+
+type scanItem struct {
+	Flag      rune
+	Name      string
+	Size      int64
+	Usage     int64
+	Mli       uint64
+	Mtime     time.Time
+	IsDir     bool
+	ItemCount int
+	Files     []*scanItem
+}
+
+// SaveScan writes analyzed dir tree to given writer so that it can be loaded later by LoadScan
+func SaveScan(dir *Dir, w io.Writer) error {
+	scan := &scanFile{
+		Version:  scanVersion,
+		BasePath: dir.BasePath,
+		Root:     createScanItem(dir),
+	}
+	if err := gob.NewEncoder(w).Encode(scan); err != nil {
+		return fmt.Errorf("saving scan: %w", err)
+	}
+	return nil
+}
+
+// LoadScan reads dir tree saved by SaveScan
+func LoadScan(r io.Reader) (*Dir, error) {
+	var scan scanFile
+	if err := gob.NewDecoder(r).Decode(&scan); err != nil {
+		return nil, fmt.Errorf("loading scan: %w", err)
+	}
+	if scan.Version != scanVersion {
+		return nil, fmt.Errorf("loading scan: unsupported version %d", scan.Version)
+	}
+	if scan.Root == nil || !scan.Root.IsDir {
+		return nil, errors.New("loading scan: root is not a directory")
+	}
+
+	dir := createItemFromScan(scan.Root, nil).(*Dir)
+	dir.BasePath = scan.BasePath
+	return dir, nil
+}
+
+func createScanItem(item Item) *scanItem {
+	res := &scanItem{
+		Flag:      item.GetFlag(),
+		Name:      item.GetName(),
+		Size:      item.GetSize(),
+		Usage:     item.GetUsage(),
+		Mtime:     item.GetMtime(),
+		IsDir:     item.IsDir(),
+		ItemCount: item.GetItemCount(),
+	}
+
+	switch item := item.(type) {
+	case *File:
+		res.Mli = item.Mli
+	case *Dir:
+		res.Files = make([]*scanItem, 0, len(item.Files))
+		for _, file := range item.Files {
+			res.Files = append(res.Files, createScanItem(file))
+		}
+	}
+	return res
+}
+
+func createItemFromScan(item *scanItem, parent *Dir) Item {
+	file := &File{
+		Flag:   item.Flag,
+		Name:   item.Name,
+		Size:   item.Size,
+		Usage:  item.Usage,
+		Mli:    item.Mli,
+		Mtime:  item.Mtime,
+		Parent: parent,
+	}
+	if !item.IsDir {
+		return file
+	}
+
+	dir := &Dir{
+		File:      file,
+		ItemCount: item.ItemCount,
+		Files:     make(Files, 0, len(item.Files)),
+	}
+	for _, child := range item.Files {
+		dir.Files = append(dir.Files, createItemFromScan(child, dir))
+	}
+	return dir
+}
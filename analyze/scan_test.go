@@ -0,0 +1,77 @@
+package analyze
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveAndLoadScan(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	analyzer := CreateAnalyzer()
+	dir := analyzer.AnalyzeDir("test_dir", func(_ string) bool { return false })
+	<-analyzer.GetDoneChan()
+
+	buff := bytes.NewBuffer(nil)
+	err := SaveScan(dir, buff)
+	assert.Nil(t, err)
+
+	loaded, err := LoadScan(buff)
+	assert.Nil(t, err)
+
+	assert.Equal(t, dir.GetPath(), loaded.GetPath())
+	assert.Equal(t, dir.Size, loaded.Size)
+	assert.Equal(t, dir.Usage, loaded.Usage)
+	assert.Equal(t, dir.ItemCount, loaded.ItemCount)
+	assert.True(t, dir.Mtime.Equal(loaded.Mtime))
+	assert.Nil(t, loaded.Parent)
+
+	nested := loaded.Files[0].(*Dir)
+	assert.Equal(t, "nested", nested.Name)
+	assert.Equal(t, dir.Files[0].GetUsage(), nested.Usage)
+	assert.Equal(t, 4, nested.ItemCount)
+	assert.Equal(t, loaded, nested.Parent)
+
+	file2 := nested.Files[0].(*File)
+	assert.Equal(t, "file2", file2.Name)
+	assert.Equal(t, int64(2), file2.Size)
+
+	file := nested.Files[1].(*Dir).Files[0]
+	assert.Equal(t, "file", file.GetName())
+	assert.Equal(t, int64(5), file.GetSize())
+	assert.Equal(t, dir.Files[0].(*Dir).Files[1].(*Dir).Files[0].GetPath(), file.GetPath())
+}
+
+func TestSaveAndLoadScanKeepsFlags(t *testing.T) {
+	dir := &Dir{
+		File:     &File{Name: "root", Flag: '.'},
+		BasePath: "/",
+	}
+	dir.Files = Files{
+		&File{Name: "link", Flag: 'H', Size: 10, Mli: 123, Parent: dir},
+		&Dir{File: &File{Name: "err", Flag: '!', Parent: dir}, ItemCount: 1},
+	}
+
+	buff := bytes.NewBuffer(nil)
+	assert.Nil(t, SaveScan(dir, buff))
+
+	loaded, err := LoadScan(buff)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "/root", loaded.GetPath())
+	assert.Equal(t, '.', loaded.Flag)
+	assert.Equal(t, 'H', loaded.Files[0].GetFlag())
+	assert.Equal(t, uint64(123), loaded.Files[0].(*File).Mli)
+	assert.Equal(t, '!', loaded.Files[1].GetFlag())
+	assert.True(t, loaded.Files[1].IsDir())
+	assert.Empty(t, loaded.Files[1].(*Dir).Files)
+}
+
+func TestLoadInvalidScan(t *testing.T) {
+	_, err := LoadScan(bytes.NewBufferString("not a scan"))
+	assert.Contains(t, err.Error(), "loading scan: ")
+}
@@ -3,6 +3,7 @@ package analyze
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -126,3 +127,24 @@ func TestSortByName(t *testing.T) {
 	assert.Equal(t, "bb", files[1].GetName())
 	assert.Equal(t, "aa", files[2].GetName())
 }
+
+func TestSortByMtime(t *testing.T) {
+	now := time.Now()
+	files := Files{
+		&File{
+			Mtime: now.Add(-time.Hour),
+		},
+		&File{
+			Mtime: now,
+		},
+		&File{
+			Mtime: now.Add(-2 * time.Hour),
+		},
+	}
+
+	sort.Sort(ByMtime(files))
+
+	assert.Equal(t, now, files[0].GetMtime())
+	assert.Equal(t, now.Add(-time.Hour), files[1].GetMtime())
+	assert.Equal(t, now.Add(-2*time.Hour), files[2].GetMtime())
+}
@@ -1,13 +1,18 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/dundee/gdu/v4/analyze"
 	"github.com/dundee/gdu/v4/build"
 	"github.com/dundee/gdu/v4/common"
 	"github.com/dundee/gdu/v4/device"
@@ -15,20 +20,112 @@ import (
 	"github.com/dundee/gdu/v4/tui"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"golang.org/x/term"
 )
 
 // Flags define flags accepted by Run
 type Flags struct {
-	LogFile          string
-	IgnoreDirs       []string
-	MaxCores         int
-	ShowDisks        bool
-	ShowApparentSize bool
-	ShowVersion      bool
-	NoColor          bool
-	NonInteractive   bool
-	NoProgress       bool
-	NoCross          bool
+	LogFile           string
+	IgnoreDirs        []string
+	IgnoreDirPatterns []string
+	IgnoreDirRegex    []string
+	ExcludeFrom       string
+	UseGitignore      bool
+	MaxCores          int
+	MaxConcurrency    int
+	FollowSymlinks    bool
+	DedupHardlinks    bool
+	FailOnReadErrors  bool
+	ReadRetries       int
+	RetryBackoff      time.Duration
+	ShowItemCount     bool
+	ShowPercentBars   bool
+	ShowRootPercent   bool
+	ShowBothSizes     bool
+	ShowXattrs        bool
+	ShowOverhead      bool
+	SparseRatio       float64
+	ShowMtime         bool
+	ShowDuration      bool
+	ShowDepth         bool
+	ShowGrowth        bool
+	GrowthFile        string
+	Verbose           bool
+	ShowFullPath      bool
+	ShowRelativePath  bool
+	DirMarker         string
+	NameWidth         int
+	NameTruncation    string
+	Width             int
+	TimeFormat        string
+	ShowInodes        bool
+	ShowAvail         bool
+	ShowDisksTotal    bool
+	DiskColumns       []string
+	DfLayout          bool
+	IncludeFsTypes    []string
+	ExcludeFsTypes    []string
+	MountPrefix       string
+	SortDisks         string
+	Summarize         bool
+	NoTotal           bool
+	RawBytes          bool
+	GroupDigits       string
+	SizePrecision     int
+	Unit              string
+	MediumSize        string
+	LargeSize         string
+	UsageWarning      float64
+	UsageCritical     float64
+	ShowDisks         bool
+	ShowApparentSize  bool
+	UseSIPrefix       bool
+	OutputFormat      string
+	PathsFrom         string
+	SaveScan          string
+	LoadScan          string
+	DiffScan          string
+	Recursive         bool
+	SortBy            string
+	SortOrder         string
+	Group             string
+	Top               int
+	LargestFiles      int
+	LargestDirs       int
+	ChildTotals       bool
+	EmptyDirs         bool
+	ZeroSizeAsEmpty   bool
+	Duplicates        bool
+	ExtensionTotals   bool
+	MergedRanking     bool
+	NullSeparated     bool
+	MaxDepth          int
+	MinSize           string
+	FailOver          string
+	OlderThan         time.Duration
+	NewerThan         time.Duration
+	NamePattern       string
+	IncludeExtensions []string
+	ExcludeExtensions []string
+	TotalMatchingOnly bool
+	NoHidden          bool
+	DirsOnly          bool
+	FilesOnly         bool
+	Timeout           time.Duration
+	ShowVersion       bool
+	NoColor           bool
+	NoSizeColor       bool
+	NoDirColor        bool
+	NoPercentColor    bool
+	NonInteractive    bool
+	NoProgress        bool
+	Quiet             bool
+	ProgressInterval  time.Duration
+	ProgressMode      string
+	Spinner           string
+	ASCIIOnly         bool
+	ProgressMessage   string
+	NoCross           bool
 }
 
 // App defines the main application
@@ -37,6 +134,7 @@ type App struct {
 	Flags   *Flags
 	Istty   bool
 	Writer  io.Writer
+	Reader  io.Reader
 	TermApp common.TermApplication
 	Getter  device.DevicesInfoGetter
 }
@@ -52,8 +150,6 @@ func (a *App) Run() error {
 		return nil
 	}
 
-	var path string
-
 	f, err := os.OpenFile(a.Flags.LogFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
 		return fmt.Errorf("opening log file: %w", err)
@@ -61,21 +157,35 @@ func (a *App) Run() error {
 	defer f.Close()
 	log.SetOutput(f)
 
-	if len(a.Args) == 1 {
-		path = a.Args[0]
-	} else {
-		path = "."
+	paths := a.Args
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	paths, err = expandGlobs(paths)
+	if err != nil {
+		return err
 	}
 
-	ui := a.createUI()
-
-	if err := a.setNoCross(path); err != nil {
+	ui, err := a.createUI()
+	if err != nil {
 		return err
 	}
 
+	for _, path := range paths {
+		if err := a.setNoCross(path); err != nil {
+			return err
+		}
+	}
+
 	ui.SetIgnoreDirPaths(a.Flags.IgnoreDirs)
 
-	if err := a.runAction(ui, path); err != nil {
+	if a.Flags.ExcludeFrom != "" {
+		if err := a.setIgnoreDirPathsFromFile(ui); err != nil {
+			return err
+		}
+	}
+
+	if err := a.runAction(ui, paths); err != nil {
 		return err
 	}
 
@@ -89,20 +199,24 @@ func (a *App) setMaxProcs() {
 
 	runtime.GOMAXPROCS(a.Flags.MaxCores)
 
+	// the message would corrupt output meant for other programs
+	if a.Flags.Quiet || a.Flags.NullSeparated || a.Flags.OutputFormat != "" && a.Flags.OutputFormat != "text" {
+		return
+	}
+
 	// runtime.GOMAXPROCS(n) with n < 1 doesn't change current setting so we use it to check current value
 	fmt.Fprintln(a.Writer, "Max cores set to "+strconv.Itoa(runtime.GOMAXPROCS(0)))
 }
 
-func (a *App) createUI() common.UI {
+func (a *App) createUI() (common.UI, error) {
 	var ui common.UI
 
 	if a.Flags.NonInteractive || !a.Istty {
-		ui = stdout.CreateStdoutUI(
-			a.Writer,
-			!a.Flags.NoColor && a.Istty,
-			!a.Flags.NoProgress && a.Istty,
-			a.Flags.ShowApparentSize,
-		)
+		stdoutUI, err := a.createStdoutUI()
+		if err != nil {
+			return nil, err
+		}
+		ui = stdoutUI
 	} else {
 		ui = tui.CreateUI(a.TermApp, !a.Flags.NoColor, a.Flags.ShowApparentSize)
 
@@ -111,7 +225,262 @@ func (a *App) createUI() common.UI {
 		}
 		tview.Styles.BorderColor = tcell.ColorDefault
 	}
-	return ui
+	return ui, nil
+}
+
+func (a *App) createStdoutUI() (*stdout.UI, error) {
+	ui := stdout.CreateStdoutUI(
+		a.Writer,
+		!a.Flags.NoColor && a.Istty,
+		// JSON progress is meant for other programs, so it is shown also when the output is not a terminal
+		!a.Flags.NoProgress && (a.Istty || strings.EqualFold(a.Flags.ProgressMode, "json")),
+		a.Flags.ShowApparentSize,
+	)
+	ui.SetUseSIPrefix(a.Flags.UseSIPrefix)
+
+	if a.Flags.OutputFormat != "" {
+		format, err := stdout.ParseOutputFormat(a.Flags.OutputFormat)
+		if err != nil {
+			return nil, err
+		}
+		ui.SetOutputFormat(format)
+	}
+	ui.SetRecursive(a.Flags.Recursive)
+
+	if a.Flags.ProgressMode != "" {
+		mode, err := stdout.ParseProgressMode(a.Flags.ProgressMode)
+		if err != nil {
+			return nil, err
+		}
+		ui.SetProgressMode(mode)
+	}
+	ui.SetSpinnerFrames(a.Flags.Spinner)
+	ui.SetASCIIOnly(a.Flags.ASCIIOnly)
+	ui.SetProgressMessage(a.Flags.ProgressMessage)
+
+	if a.Flags.SortBy != "" {
+		if err := ui.SetSorting(a.Flags.SortBy, a.Flags.SortOrder); err != nil {
+			return nil, err
+		}
+	}
+	if err := ui.SetGrouping(a.Flags.Group); err != nil {
+		return nil, err
+	}
+
+	if err := ui.SetIgnoreDirPatterns(a.Flags.IgnoreDirPatterns); err != nil {
+		return nil, err
+	}
+
+	if err := ui.SetIgnoreDirRegex(a.Flags.IgnoreDirRegex); err != nil {
+		return nil, err
+	}
+	ui.SetUseGitignore(a.Flags.UseGitignore)
+
+	if err := ui.SetNamePattern(a.Flags.NamePattern); err != nil {
+		return nil, err
+	}
+
+	ui.SetMaxEntries(a.Flags.Top)
+	ui.SetMaxDepth(a.Flags.MaxDepth)
+	ui.SetShowHidden(!a.Flags.NoHidden)
+	if a.Flags.DirsOnly && a.Flags.LargestFiles > 0 {
+		return nil, errors.New("dirs-only and largest-files options cannot be used together")
+	}
+	ui.SetDirsOnly(a.Flags.DirsOnly)
+	if a.Flags.DirsOnly && a.Flags.FilesOnly {
+		return nil, errors.New("dirs-only and files-only options cannot be used together")
+	}
+	if a.Flags.FilesOnly && a.Flags.LargestDirs > 0 {
+		return nil, errors.New("files-only and largest-dirs options cannot be used together")
+	}
+	ui.SetFilesOnly(a.Flags.FilesOnly)
+	ui.SetOlderThan(a.Flags.OlderThan)
+	ui.SetNewerThan(a.Flags.NewerThan)
+	ui.SetIncludeExtensions(a.Flags.IncludeExtensions)
+	ui.SetExcludeExtensions(a.Flags.ExcludeExtensions)
+	ui.SetTotalMatchingOnly(a.Flags.TotalMatchingOnly)
+	ui.SetTimeout(a.Flags.Timeout)
+	ui.SetProgressInterval(a.Flags.ProgressInterval)
+	ui.SetMaxConcurrency(a.Flags.MaxConcurrency)
+	ui.SetFollowSymlinks(a.Flags.FollowSymlinks)
+	ui.SetDedupHardlinks(a.Flags.DedupHardlinks)
+	ui.SetFailOnReadErrors(a.Flags.FailOnReadErrors)
+	ui.SetCrossFilesystems(!a.Flags.NoCross)
+	if err := ui.SetReadRetries(a.Flags.ReadRetries, a.Flags.RetryBackoff); err != nil {
+		return nil, err
+	}
+	ui.SetShowItemCount(a.Flags.ShowItemCount)
+	ui.SetShowPercentBars(a.Flags.ShowPercentBars)
+	ui.SetShowRootPercent(a.Flags.ShowRootPercent)
+	ui.SetShowBothSizes(a.Flags.ShowBothSizes)
+	if err := ui.SetShowXattrs(a.Flags.ShowXattrs); err != nil {
+		return nil, err
+	}
+	ui.SetShowOverhead(a.Flags.ShowOverhead)
+	if err := ui.SetSparseRatio(a.Flags.SparseRatio); err != nil {
+		return nil, err
+	}
+	ui.SetShowMtime(a.Flags.ShowMtime)
+	ui.SetShowDuration(a.Flags.ShowDuration)
+	ui.SetShowDepth(a.Flags.ShowDepth)
+	if a.Flags.ShowGrowth {
+		path := a.Flags.GrowthFile
+		if path == "" {
+			cacheDir, err := os.UserCacheDir()
+			if err != nil {
+				return nil, fmt.Errorf("locating growth file: %w", err)
+			}
+			path = filepath.Join(cacheDir, "gdu", "growth.json")
+		}
+		ui.SetGrowthFile(path)
+	}
+	ui.SetVerbose(a.Flags.Verbose)
+	if a.Flags.ShowFullPath && a.Flags.ShowRelativePath {
+		return nil, errors.New("show-full-path and show-relative-path options cannot be used together")
+	}
+	ui.SetShowFullPath(a.Flags.ShowFullPath)
+	ui.SetShowRelativePath(a.Flags.ShowRelativePath)
+	if err := ui.SetDirMarker(a.Flags.DirMarker); err != nil {
+		return nil, err
+	}
+	ui.SetNameWidth(a.Flags.NameWidth)
+	if err := ui.SetNameTruncation(a.Flags.NameTruncation); err != nil {
+		return nil, err
+	}
+	width := a.Flags.Width
+	if width == 0 && a.Istty {
+		width = getTerminalWidth()
+	}
+	ui.SetTerminalWidth(width)
+	ui.SetTimeFormat(a.Flags.TimeFormat)
+	ui.SetShowInodes(a.Flags.ShowInodes)
+	ui.SetShowAvail(a.Flags.ShowAvail)
+	ui.SetShowDevicesTotal(a.Flags.ShowDisksTotal)
+	if err := ui.SetDeviceColumns(a.Flags.DiskColumns); err != nil {
+		return nil, err
+	}
+	if a.Flags.DfLayout && a.Flags.OutputFormat != "" && a.Flags.OutputFormat != "text" {
+		return nil, errors.New("df layout is supported only in text format")
+	}
+	if a.Flags.DfLayout && len(a.Flags.DiskColumns) > 0 {
+		return nil, errors.New("df layout and disk-columns options cannot be used together")
+	}
+	ui.SetDfLayout(a.Flags.DfLayout)
+	ui.SetIncludeFsTypes(a.Flags.IncludeFsTypes)
+	ui.SetExcludeFsTypes(a.Flags.ExcludeFsTypes)
+	ui.SetMountPrefix(a.Flags.MountPrefix)
+	if a.Flags.SortDisks != "" {
+		if err := ui.SetDeviceSorting(a.Flags.SortDisks, a.Flags.SortOrder); err != nil {
+			return nil, err
+		}
+	}
+	if a.Flags.Summarize && a.Flags.NoTotal {
+		return nil, errors.New("summarize and no-total options cannot be used together")
+	}
+	ui.SetSummarizeOnly(a.Flags.Summarize)
+	ui.SetQuiet(a.Flags.Quiet)
+	if a.Flags.LargestFiles > 0 && a.Flags.LargestDirs > 0 {
+		return nil, errors.New("largest-files and largest-dirs options cannot be used together")
+	}
+	if (a.Flags.LargestFiles > 0 || a.Flags.LargestDirs > 0) &&
+		a.Flags.OutputFormat != "" && a.Flags.OutputFormat != "text" {
+		return nil, errors.New("listing of largest files or dirs is supported only in text format")
+	}
+	if a.Flags.ChildTotals && a.Flags.OutputFormat != "" && a.Flags.OutputFormat != "text" {
+		return nil, errors.New("totals of subdirectories are supported only in text format")
+	}
+	ui.SetChildTotals(a.Flags.ChildTotals)
+	if a.Flags.EmptyDirs && a.Flags.OutputFormat != "" && a.Flags.OutputFormat != "text" {
+		return nil, errors.New("listing of empty dirs is supported only in text format")
+	}
+	ui.SetEmptyDirs(a.Flags.EmptyDirs)
+	ui.SetZeroSizeDirsAsEmpty(a.Flags.ZeroSizeAsEmpty)
+	if a.Flags.Duplicates && a.Flags.OutputFormat != "" && a.Flags.OutputFormat != "text" {
+		return nil, errors.New("listing of duplicate files is supported only in text format")
+	}
+	ui.SetDuplicates(a.Flags.Duplicates)
+	if a.Flags.ExtensionTotals && a.Flags.OutputFormat != "" && a.Flags.OutputFormat != "text" {
+		return nil, errors.New("totals by extension are supported only in text format")
+	}
+	ui.SetExtensionTotals(a.Flags.ExtensionTotals)
+	if a.Flags.MergedRanking && a.Flags.OutputFormat != "" && a.Flags.OutputFormat != "text" {
+		return nil, errors.New("merged ranking of paths is supported only in text format")
+	}
+	if a.Flags.MergedRanking && (a.Flags.LargestFiles > 0 || a.Flags.LargestDirs > 0 ||
+		a.Flags.ChildTotals || a.Flags.EmptyDirs || a.Flags.Duplicates || a.Flags.ExtensionTotals) {
+		return nil, errors.New("merged ranking of paths can be printed only for the listing of items")
+	}
+	ui.SetMergedRanking(a.Flags.MergedRanking)
+	if a.Flags.NullSeparated && a.Flags.OutputFormat != "" && a.Flags.OutputFormat != "text" {
+		return nil, errors.New("null-separated paths cannot be printed in other output formats")
+	}
+	if a.Flags.NullSeparated && (a.Flags.LargestFiles > 0 || a.Flags.LargestDirs > 0 ||
+		a.Flags.ChildTotals || a.Flags.EmptyDirs || a.Flags.Duplicates || a.Flags.ExtensionTotals) {
+		return nil, errors.New("null-separated paths can be printed only for the listing of items")
+	}
+	ui.SetNullSeparated(a.Flags.NullSeparated)
+	ui.SetLargestFiles(a.Flags.LargestFiles)
+	ui.SetLargestDirs(a.Flags.LargestDirs)
+	ui.SetShowTotal(!a.Flags.NoTotal)
+	ui.SetRawBytes(a.Flags.RawBytes)
+	if err := ui.SetDigitGrouping(a.Flags.GroupDigits); err != nil {
+		return nil, err
+	}
+	if err := ui.SetSizePrecision(a.Flags.SizePrecision); err != nil {
+		return nil, err
+	}
+	if a.Flags.Unit != "" {
+		unit, err := stdout.ParseSizeUnit(a.Flags.Unit)
+		if err != nil {
+			return nil, err
+		}
+		ui.SetForceUnit(unit)
+	}
+
+	if a.Flags.MinSize != "" {
+		minSize, err := stdout.ParseSize(a.Flags.MinSize)
+		if err != nil {
+			return nil, err
+		}
+		ui.SetMinSize(minSize)
+	}
+
+	mediumSize, err := parseOptionalSize(a.Flags.MediumSize)
+	if err != nil {
+		return nil, err
+	}
+	largeSize, err := parseOptionalSize(a.Flags.LargeSize)
+	if err != nil {
+		return nil, err
+	}
+	ui.SetSizeThresholds(mediumSize, largeSize)
+	failOver, err := parseOptionalSize(a.Flags.FailOver)
+	if err != nil {
+		return nil, err
+	}
+	ui.SetFailOver(failOver)
+	ui.SetColorSize(!a.Flags.NoSizeColor)
+	ui.SetColorDir(!a.Flags.NoDirColor)
+	ui.SetColorPercent(!a.Flags.NoPercentColor)
+	ui.SetUsageThresholds(a.Flags.UsageWarning, a.Flags.UsageCritical)
+
+	return ui, nil
+}
+
+func (a *App) setIgnoreDirPathsFromFile(ui common.UI) error {
+	stdoutUI, ok := ui.(*stdout.UI)
+	if !ok {
+		return errors.New("reading paths to ignore from file is supported only in non-interactive mode")
+	}
+	return stdoutUI.SetIgnoreDirPathsFromFile(a.Flags.ExcludeFrom)
+}
+
+// parseOptionalSize parses size given by flag, empty value means 0
+func parseOptionalSize(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return stdout.ParseSize(value)
 }
 
 func (a *App) setNoCross(path string) error {
@@ -126,15 +495,167 @@ func (a *App) setNoCross(path string) error {
 	return nil
 }
 
-func (a *App) runAction(ui common.UI, path string) error {
+func (a *App) runAction(ui common.UI, paths []string) error {
 	if a.Flags.ShowDisks {
 		if err := ui.ListDevices(a.Getter); err != nil {
 			return fmt.Errorf("loading mount points: %w", err)
 		}
-	} else {
-		if err := ui.AnalyzePath(path, nil); err != nil {
+	} else if a.Flags.PathsFrom != "" {
+		return a.analyzePathsFrom(ui)
+	} else if a.Flags.DiffScan != "" {
+		return a.diffScan(ui, paths)
+	} else if a.Flags.LoadScan != "" {
+		return a.loadScan(ui)
+	} else if len(paths) > 1 {
+		if a.Flags.SaveScan != "" {
+			return errors.New("saving scan is supported only when analyzing single path")
+		}
+		stdoutUI, ok := ui.(*stdout.UI)
+		if !ok {
+			return errors.New("analyzing multiple paths is supported only in non-interactive mode")
+		}
+		if err := stdoutUI.AnalyzePaths(paths); err != nil {
+			return fmt.Errorf("scanning dirs: %w", err)
+		}
+	} else if a.Flags.SaveScan != "" {
+		f, err := a.setScanOutput(ui)
+		if err != nil {
+			return err
+		}
+		err = ui.AnalyzePath(paths[0], nil)
+		// the scan is not complete until the file is closed
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			return fmt.Errorf("saving scan: %w", closeErr)
+		}
+		if err != nil {
 			return fmt.Errorf("scanning dir: %w", err)
 		}
+	} else if err := ui.AnalyzePath(paths[0], nil); err != nil {
+		return fmt.Errorf("scanning dir: %w", err)
+	}
+	return nil
+}
+
+// expandGlobs replaces paths containing glob patterns (e.g. quoted "/home/*/Downloads")
+// by the matching paths. Existing paths are kept as they are.
+func expandGlobs(paths []string) ([]string, error) {
+	res := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !strings.ContainsAny(path, "*?[") {
+			res = append(res, path)
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			res = append(res, path)
+			continue
+		}
+
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %s: %w", path, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no paths match pattern %s", path)
+		}
+		res = append(res, matches...)
+	}
+	return res, nil
+}
+
+func (a *App) analyzePathsFrom(ui common.UI) error {
+	stdoutUI, ok := ui.(*stdout.UI)
+	if !ok {
+		return errors.New("reading paths is supported only in non-interactive mode")
+	}
+
+	input := a.Reader
+	if a.Flags.PathsFrom != "-" {
+		f, err := os.Open(a.Flags.PathsFrom)
+		if err != nil {
+			return fmt.Errorf("opening file with paths: %w", err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	if err := stdoutUI.AnalyzePathsFromReader(input); err != nil {
+		return fmt.Errorf("scanning dirs: %w", err)
 	}
 	return nil
 }
+
+func (a *App) setScanOutput(ui common.UI) (*os.File, error) {
+	stdoutUI, ok := ui.(*stdout.UI)
+	if !ok {
+		return nil, errors.New("saving scan is supported only in non-interactive mode")
+	}
+
+	f, err := os.Create(a.Flags.SaveScan)
+	if err != nil {
+		return nil, fmt.Errorf("creating scan file: %w", err)
+	}
+	stdoutUI.SetScanOutput(f)
+	return f, nil
+}
+
+func (a *App) loadScan(ui common.UI) error {
+	stdoutUI, ok := ui.(*stdout.UI)
+	if !ok {
+		return errors.New("loading scan is supported only in non-interactive mode")
+	}
+
+	f, err := os.Open(a.Flags.LoadScan)
+	if err != nil {
+		return fmt.Errorf("opening scan file: %w", err)
+	}
+	defer f.Close()
+
+	return stdoutUI.PrintScan(f)
+}
+
+func (a *App) diffScan(ui common.UI, paths []string) error {
+	stdoutUI, ok := ui.(*stdout.UI)
+	if !ok {
+		return errors.New("comparing scans is supported only in non-interactive mode")
+	}
+
+	oldDir, err := loadScanFile(a.Flags.DiffScan)
+	if err != nil {
+		return err
+	}
+
+	if a.Flags.LoadScan == "" {
+		if len(paths) > 1 {
+			return errors.New("comparing scans is supported only when analyzing single path")
+		}
+		if err := stdoutUI.DiffPath(paths[0], oldDir); err != nil {
+			return fmt.Errorf("scanning dir: %w", err)
+		}
+		return nil
+	}
+
+	newDir, err := loadScanFile(a.Flags.LoadScan)
+	if err != nil {
+		return err
+	}
+	return stdoutUI.DiffScans(oldDir, newDir, a.Writer)
+}
+
+func loadScanFile(path string) (*analyze.Dir, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening scan file: %w", err)
+	}
+	defer f.Close()
+
+	return analyze.LoadScan(f)
+}
+
+// getTerminalWidth returns width of the terminal of stdout or 0 if it cannot be detected
+func getTerminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
@@ -2,6 +2,10 @@ package app
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -10,6 +14,7 @@ import (
 	"github.com/dundee/gdu/v4/internal/testapp"
 	"github.com/dundee/gdu/v4/internal/testdev"
 	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/dundee/gdu/v4/stdout"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -52,6 +57,498 @@ func TestAnalyzePath(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestAnalyzePathWithWidth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/a_file_with_very_long_name", []byte("xxx"), 0644)
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", Width: 20},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Contains(t, out, " /nested\n")
+	assert.Contains(t, out, " a_file_…\n")
+	assert.Nil(t, err)
+}
+
+func TestAnalyzePathJSON(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", OutputFormat: "json"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Contains(t, out, `"name": "nested"`)
+	assert.Nil(t, err)
+}
+
+func TestEmptyDirs(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.Mkdir("test_dir/empty", 0755)
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", EmptyDirs: true},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Contains(t, out, "/test_dir/empty\nTotal: 1 empty dir")
+	assert.Nil(t, err)
+}
+
+func TestDuplicates(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/copy", []byte("hello"), 0644)
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", Duplicates: true},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Contains(t, out, "2 copies of 5 B, 5 B wasted:")
+	assert.Nil(t, err)
+}
+
+func TestShowDuration(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", ShowDuration: true},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Contains(t, out, "Scan duration: ")
+	assert.Nil(t, err)
+}
+
+func TestShowGrowth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	growthFile := "growth.json"
+	defer os.Remove(growthFile)
+
+	flags := &Flags{LogFile: "/dev/null", ShowGrowth: true, GrowthFile: growthFile, ShowApparentSize: true}
+	out, err := runApp(flags, []string{"test_dir"}, false, testdev.DevicesInfoGetterMock{})
+	assert.Contains(t, out, "No previous scan to compare with")
+	assert.Nil(t, err)
+
+	os.WriteFile("test_dir/new_file", []byte("abc"), 0644)
+
+	out, err = runApp(flags, []string{"test_dir"}, false, testdev.DevicesInfoGetterMock{})
+	assert.Contains(t, out, "+3 B since last scan")
+	assert.Nil(t, err)
+}
+
+func TestAnalyzePathNDJSON(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", OutputFormat: "ndjson"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Contains(t, out, `/test_dir/nested","size":`)
+	assert.Nil(t, err)
+}
+
+func TestAnalyzePathFlat(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", OutputFormat: "flat", ShowApparentSize: true},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.True(t, strings.HasPrefix(out, "5\t"))
+	assert.Contains(t, out, "/test_dir/nested/subnested/file\n2\t")
+	assert.Nil(t, err)
+}
+
+func TestAnalyzePathWithUnknownFormat(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", OutputFormat: "yaml"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Empty(t, out)
+	assert.Equal(t, "unknown output format: yaml", err.Error())
+}
+
+func TestAnalyzePathSortedByName(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", SortBy: "name", SortOrder: "asc"},
+		[]string{"test_dir/nested"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Regexp(t, "(?s)file2.*subnested", out)
+	assert.Nil(t, err)
+}
+
+func TestAnalyzePathWithUnknownSortKey(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", SortBy: "color", SortOrder: "asc"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "unknown sort key: color", err.Error())
+}
+
+func TestAnalyzePathWithMinSize(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", MinSize: "1M"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.NotContains(t, out, "nested")
+	assert.Contains(t, out, "Total:")
+	assert.Nil(t, err)
+}
+
+func TestAnalyzePathWithInvalidMinSize(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", MinSize: "10X"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "invalid size: 10X", err.Error())
+}
+
+func TestAnalyzePathWithInvalidLargeSize(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", MediumSize: "10M", LargeSize: "1X"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "invalid size: 1X", err.Error())
+}
+
+func TestAnalyzePathWithInvalidProgressMode(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", ProgressMode: "fancy"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "unknown progress mode: fancy", err.Error())
+}
+
+func TestSummarizeWithNoTotal(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", Summarize: true, NoTotal: true},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "summarize and no-total options cannot be used together", err.Error())
+}
+
+func TestFailOver(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", FailOver: "1K"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Contains(t, out, "nested")
+	assert.True(t, errors.Is(err, stdout.ErrSizeOverThreshold))
+}
+
+func TestQuietFailOver(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", FailOver: "1K", Quiet: true},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "", out)
+	assert.True(t, errors.Is(err, stdout.ErrSizeOverThreshold))
+}
+
+func TestFullAndRelativePath(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", ShowFullPath: true, ShowRelativePath: true},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "show-full-path and show-relative-path options cannot be used together", err.Error())
+}
+
+func TestLargestFilesInJSON(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", LargestFiles: 5, OutputFormat: "json"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "listing of largest files or dirs is supported only in text format", err.Error())
+}
+
+func TestChildTotalsInJSON(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", ChildTotals: true, OutputFormat: "json"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "totals of subdirectories are supported only in text format", err.Error())
+}
+
+func TestEmptyDirsInJSON(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", EmptyDirs: true, OutputFormat: "json"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "listing of empty dirs is supported only in text format", err.Error())
+}
+
+func TestDuplicatesInJSON(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", Duplicates: true, OutputFormat: "json"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "listing of duplicate files is supported only in text format", err.Error())
+}
+
+func TestExtensionTotalsInJSON(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", ExtensionTotals: true, OutputFormat: "json"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "totals by extension are supported only in text format", err.Error())
+}
+
+func TestMergedRankingWithLargestFiles(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", MergedRanking: true, LargestFiles: 5},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "merged ranking of paths can be printed only for the listing of items", err.Error())
+}
+
+func TestNullSeparatedInJSON(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", NullSeparated: true, OutputFormat: "json"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "null-separated paths cannot be printed in other output formats", err.Error())
+}
+
+func TestNullSeparatedWithLargestFiles(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", NullSeparated: true, LargestFiles: 5},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "null-separated paths can be printed only for the listing of items", err.Error())
+}
+
+func TestDirsOnlyWithLargestFiles(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", DirsOnly: true, LargestFiles: 5},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "dirs-only and largest-files options cannot be used together", err.Error())
+}
+
+func TestDirsOnlyWithFilesOnly(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", DirsOnly: true, FilesOnly: true},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "dirs-only and files-only options cannot be used together", err.Error())
+}
+
+func TestFilesOnlyWithLargestDirs(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", FilesOnly: true, LargestDirs: 5},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "files-only and largest-dirs options cannot be used together", err.Error())
+}
+
+func TestLargestFilesAndDirs(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", LargestFiles: 5, LargestDirs: 5},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "largest-files and largest-dirs options cannot be used together", err.Error())
+}
+
+func TestAnalyzePathWithInvalidNamePattern(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", NamePattern: "[a-"},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "invalid name pattern [a-: syntax error in pattern", err.Error())
+}
+
+func TestAnalyzePathWithExcludeFrom(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/excludes", []byte("*/subnested\n"), 0644)
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", ExcludeFrom: "test_dir/excludes", Recursive: true},
+		[]string{"test_dir/nested"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Nil(t, err)
+	assert.Contains(t, out, "file2")
+	assert.NotContains(t, out, "subnested")
+}
+
 func TestAnalyzePathWithGui(t *testing.T) {
 	fin := testdir.CreateTestDir()
 	defer fin()
@@ -67,6 +564,193 @@ func TestAnalyzePathWithGui(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestAnalyzeMultiplePaths(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null"},
+		[]string{"test_dir/nested", "test_dir/nested/subnested"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Contains(t, out, "subnested:")
+	assert.Contains(t, out, "Grand total:")
+	assert.Nil(t, err)
+}
+
+func TestAnalyzeGlob(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+	os.MkdirAll("test_dir/other/subnested", os.ModePerm)
+	os.WriteFile("test_dir/nested/file.gz", []byte("gz"), 0644)
+	os.WriteFile("test_dir/other/log.gz", []byte("gz"), 0644)
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null"},
+		[]string{"test_dir/*/subnested"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+	assert.Nil(t, err)
+
+	nested, _ := filepath.Abs("test_dir/nested/subnested")
+	other, _ := filepath.Abs("test_dir/other/subnested")
+	assert.Contains(t, out, nested+":\n")
+	assert.Contains(t, out, other+":\n")
+	assert.Contains(t, out, "Grand total:")
+
+	out, err = runApp(
+		&Flags{LogFile: "/dev/null"},
+		[]string{"test_dir/*/*.gz"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+	assert.Nil(t, err)
+	assert.Contains(t, out, "file.gz:\n")
+	assert.Contains(t, out, "log.gz:\n")
+	assert.NotContains(t, out, "file2")
+}
+
+func TestAnalyzeGlobWithSingleMatch(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null"},
+		[]string{"test_dir/nest?d"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Nil(t, err)
+	assert.Contains(t, out, "/subnested")
+	assert.NotContains(t, out, "Grand total:")
+}
+
+func TestAnalyzeGlobWithoutMatch(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null"},
+		[]string{"test_dir/*/missing*"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+	assert.Equal(t, "no paths match pattern test_dir/*/missing*", err.Error())
+
+	_, err = runApp(
+		&Flags{LogFile: "/dev/null"},
+		[]string{"test_dir/[a"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+	assert.Equal(t, "invalid glob pattern test_dir/[a: syntax error in pattern", err.Error())
+}
+
+func TestAnalyzePathsFromStdin(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	buff := bytes.NewBuffer(nil)
+	app := App{
+		Flags:  &Flags{LogFile: "/dev/null", PathsFrom: "-"},
+		Istty:  false,
+		Writer: buff,
+		Reader: strings.NewReader("test_dir/nested\ntest_dir/nested/subnested\n"),
+		Getter: testdev.DevicesInfoGetterMock{},
+	}
+	err := app.Run()
+
+	assert.Nil(t, err)
+	assert.Contains(t, buff.String(), "subnested:")
+	assert.Contains(t, buff.String(), "Grand total:")
+}
+
+func TestAnalyzePathsFromMissingFile(t *testing.T) {
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", PathsFrom: "missing.txt"},
+		[]string{},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+	assert.Contains(t, err.Error(), "opening file with paths")
+}
+
+func TestSaveAndLoadScan(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", SaveScan: "test_dir/scan.gdu"},
+		[]string{"test_dir/nested"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+	assert.Nil(t, err)
+	assert.Contains(t, out, "subnested")
+
+	loaded, err := runApp(
+		&Flags{LogFile: "/dev/null", LoadScan: "test_dir/scan.gdu"},
+		[]string{},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, out, loaded)
+}
+
+func TestDiffScan(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", SaveScan: "test_dir/scan.gdu"},
+		[]string{"test_dir/nested"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+	assert.Nil(t, err)
+
+	os.WriteFile("test_dir/nested/added", []byte("abc"), 0644)
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", DiffScan: "test_dir/scan.gdu", ShowApparentSize: true},
+		[]string{"test_dir/nested"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+	assert.Nil(t, err)
+	assert.Contains(t, out, "+        +3 B         3 B added")
+	assert.Contains(t, out, "Total: ")
+}
+
+func TestLoadMissingScan(t *testing.T) {
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", LoadScan: "missing.gdu"},
+		[]string{},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+	assert.Contains(t, err.Error(), "opening scan file")
+}
+
+func TestAnalyzeMultiplePathsWithGui(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null"},
+		[]string{"test_dir/nested", "test_dir/nested/subnested"},
+		true,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "analyzing multiple paths is supported only in non-interactive mode", err.Error())
+}
+
 func TestAnalyzePathWithErr(t *testing.T) {
 	fin := testdir.CreateTestDir()
 	defer fin()
@@ -127,6 +811,32 @@ func TestListDevices(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestListDevicesInDfLayout(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", ShowDisks: true, DfLayout: true},
+		[]string{},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "Filesystem      Size  Used Avail Use% Mounted on", out)
+	assert.Nil(t, err)
+}
+
+func TestDfLayoutWithDiskColumns(t *testing.T) {
+	_, err := runApp(
+		&Flags{LogFile: "/dev/null", ShowDisks: true, DfLayout: true, DiskColumns: []string{"name"}},
+		[]string{},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Equal(t, "df layout and disk-columns options cannot be used together", err.Error())
+}
+
 func TestListDevicesWithErr(t *testing.T) {
 	fin := testdir.CreateTestDir()
 	defer fin()
@@ -169,6 +879,22 @@ func TestMaxCores(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestMaxCoresWithJSONOutput(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	out, err := runApp(
+		&Flags{LogFile: "/dev/null", MaxCores: 1, OutputFormat: "json", NonInteractive: true},
+		[]string{"test_dir"},
+		false,
+		testdev.DevicesInfoGetterMock{},
+	)
+
+	assert.Nil(t, err)
+	var result interface{}
+	assert.Nil(t, json.Unmarshal([]byte(out), &result), out)
+}
+
 func TestMaxCoresHighEdge(t *testing.T) {
 	out, err := runApp(
 		&Flags{LogFile: "/dev/null", MaxCores: runtime.NumCPU() + 1},
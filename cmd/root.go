@@ -5,6 +5,7 @@ import (
 	"os"
 	"runtime"
 	"strconv"
+	"time"
 
 	"github.com/dundee/gdu/v4/cmd/app"
 	"github.com/dundee/gdu/v4/device"
@@ -17,14 +18,14 @@ import (
 var af *app.Flags
 
 var rootCmd = &cobra.Command{
-	Use:   "gdu [directory_to_scan]",
+	Use:   "gdu [directory_to_scan ...]",
 	Short: "Pretty fast disk usage analyzer written in Go",
 	Long: `Pretty fast disk usage analyzer written in Go.
 
 Gdu is intended primarily for SSD disks where it can fully utilize parallel processing.
 However HDDs work as well, but the performance gain is not so huge.
 `,
-	Args:         cobra.MaximumNArgs(1),
+	Args:         cobra.ArbitraryArgs,
 	SilenceUsage: true,
 	RunE:         runE,
 }
@@ -34,13 +35,104 @@ func init() {
 	flags := rootCmd.Flags()
 	flags.StringVarP(&af.LogFile, "log-file", "l", "/dev/null", "Path to a logfile")
 	flags.StringSliceVarP(&af.IgnoreDirs, "ignore-dirs", "i", []string{"/proc", "/dev", "/sys", "/run"}, "Absolute paths to ignore (separated by comma)")
+	flags.StringSliceVarP(&af.IgnoreDirPatterns, "ignore-dirs-pattern", "I", []string{}, "Glob patterns of paths to ignore in non-interactive mode (separated by comma)")
+	flags.StringArrayVar(&af.IgnoreDirRegex, "ignore-dirs-regex", []string{}, "Regular expression of paths to ignore in non-interactive mode (can be used multiple times)")
+	flags.StringVar(&af.ExcludeFrom, "exclude-from", "", "Read paths to ignore from given file, one per line (glob patterns, regular expressions prefixed by 're:'), in non-interactive mode")
+	flags.BoolVar(&af.UseGitignore, "gitignore", false, "Ignore paths matched by .gitignore files in non-interactive mode")
 	flags.IntVarP(&af.MaxCores, "max-cores", "m", runtime.NumCPU(), "Set max cores that GDU will use. " + strconv.Itoa(runtime.NumCPU()) + " cores available")
+	flags.IntVar(&af.MaxConcurrency, "max-concurrency", 0, "Maximal number of directories read concurrently in non-interactive mode (0 means default)")
 	flags.BoolVarP(&af.ShowDisks, "show-disks", "d", false, "Show all mounted disks")
 	flags.BoolVarP(&af.ShowApparentSize, "show-apparent-size", "a", false, "Show apparent size")
+	flags.BoolVar(&af.ShowBothSizes, "show-both-sizes", false, "Show apparent size next to disk usage (or disk usage next to apparent size with --show-apparent-size) in non-interactive mode")
+	flags.BoolVar(&af.ShowXattrs, "show-xattrs", false, "Show size of extended attributes (and ACLs) of each item and in total in non-interactive mode (on Linux, macOS and BSD)")
+	flags.BoolVar(&af.ShowOverhead, "show-overhead", false, "Show overhead of each item (disk usage above apparent size caused by block rounding) and in total in non-interactive mode")
+	flags.Float64Var(&af.SparseRatio, "sparse-ratio", 0, "Mark files with disk usage smaller than given ratio of their apparent size (e.g. 0.5) as sparse by 'S' in non-interactive mode (0 means no marking)")
+	flags.BoolVar(&af.UseSIPrefix, "si", false, "Show sizes with decimal SI prefixes (KB, MB, GB) instead of binary prefixes in non-interactive mode")
+	flags.StringVarP(&af.OutputFormat, "format", "f", "text", "Output format for non-interactive mode (text, json, ndjson, ncdu, csv, tsv, flat, html, markdown, xml), only text and json for --show-disks")
+	flags.StringVar(&af.PathsFrom, "paths-from", "", "Analyze paths read from given file, one per line ('-' means stdin), in non-interactive mode")
+	flags.StringVar(&af.SaveScan, "save-scan", "", "Save the analyzed tree to given file to be loaded later by --load-scan in non-interactive mode")
+	flags.StringVar(&af.LoadScan, "load-scan", "", "Print the analyzed tree saved by --save-scan instead of analyzing in non-interactive mode")
+	flags.StringVar(&af.DiffScan, "diff-scan", "", "Print items changed since the analysis saved by --save-scan in non-interactive mode (compared with --load-scan if given)")
+	flags.BoolVarP(&af.Recursive, "recursive", "r", false, "Print whole directory tree in non-interactive mode")
+	flags.StringVar(&af.SortBy, "sort", "size", "Sort items by size, name, itemCount, mtime or none (in order of analysis, saves memory in huge dirs) in non-interactive mode")
+	flags.StringVar(&af.SortOrder, "sort-order", "desc", "Sort order (asc, desc) in non-interactive mode")
+	flags.StringVar(&af.Group, "group", "", "List all directories before files (dirs-first) or after them (files-first), each group sorted by --sort, in non-interactive mode")
+	flags.IntVarP(&af.Top, "top", "t", 0, "Show only given number of largest items in non-interactive mode (0 means all)")
+	flags.IntVar(&af.LargestFiles, "largest-files", 0, "Print given number of the largest files from the whole directory tree with full paths in non-interactive mode")
+	flags.IntVar(&af.LargestDirs, "largest-dirs", 0, "Print given number of the largest directories from the whole directory tree (including nested ones) with full paths in non-interactive mode")
+	flags.BoolVar(&af.ChildTotals, "child-totals", false, "Print only total size and item count of each immediate subdirectory in non-interactive mode")
+	flags.BoolVar(&af.EmptyDirs, "empty-dirs", false, "Print only paths of empty directories found anywhere in the tree and their count in non-interactive mode")
+	flags.BoolVar(&af.ZeroSizeAsEmpty, "zero-size-empty", false, "Report also directories containing only empty files with --empty-dirs")
+	flags.BoolVar(&af.Duplicates, "duplicates", false, "Print only groups of files with the same content and space wasted by them in non-interactive mode (files of the same size are read and hashed)")
+	flags.BoolVar(&af.ExtensionTotals, "by-extension", false, "Print only total size and count of files of each extension found anywhere in the tree in non-interactive mode")
+	flags.BoolVar(&af.MergedRanking, "merge-paths", false, "Print top-level items of all given paths in a single ranking, each with the path it comes from, in non-interactive mode")
+	flags.BoolVarP(&af.NullSeparated, "null", "0", false, "Print only full paths of listed items, each terminated by NUL byte (for xargs -0), in non-interactive mode")
+	flags.IntVar(&af.MaxDepth, "max-depth", 0, "Print directory tree down to given depth in non-interactive mode (0 means only the top level)")
+	flags.StringVar(&af.MinSize, "min-size", "", "Hide items smaller than given size (e.g. 10M, 1.5G) in non-interactive mode")
+	flags.DurationVar(&af.OlderThan, "older-than", 0, "Show only files modified before given duration (e.g. 720h) and dirs containing them in non-interactive mode")
+	flags.DurationVar(&af.NewerThan, "newer-than", 0, "Show only files modified within given duration (e.g. 24h) and dirs containing them in non-interactive mode")
+	flags.StringVar(&af.NamePattern, "name", "", "Show only items with name matching given glob pattern (e.g. '*.mp4') and dirs containing them, total counts only matching files, in non-interactive mode")
+	flags.StringSliceVar(&af.IncludeExtensions, "include-ext", []string{}, "Show only files with given extensions (e.g. log,tar.gz) and dirs containing them in non-interactive mode")
+	flags.StringSliceVar(&af.ExcludeExtensions, "exclude-ext", []string{}, "Hide files with given extensions (e.g. iso) in non-interactive mode")
+	flags.BoolVar(&af.TotalMatchingOnly, "total-matching-only", false, "Count only files matching --older-than, --newer-than, --include-ext and --exclude-ext in the total in non-interactive mode")
+	flags.DurationVar(&af.Timeout, "timeout", 0, "Abort the analysis after given duration (e.g. 30s, 5m) in non-interactive mode (0 means no limit)")
+	flags.BoolVarP(&af.FollowSymlinks, "follow-symlinks", "L", false, "Follow symlinks in non-interactive mode (dirs linked multiple times are counted multiple times)")
+	flags.BoolVar(&af.DedupHardlinks, "dedup-hardlinks", false, "Show size of hardlinked files only for the first found link in non-interactive mode")
+	flags.BoolVar(&af.FailOnReadErrors, "fail-on-read-errors", false, "Fail when some directory cannot be read (e.g. because of permissions) in non-interactive mode")
+	flags.IntVar(&af.ReadRetries, "read-retries", 0, "Retry reading of a directory failed with a transient error (e.g. on NFS or SMB mount) given number of times in non-interactive mode")
+	flags.DurationVar(&af.RetryBackoff, "retry-backoff", 100*time.Millisecond, "Delay before the first retry of a directory read, doubled before each next one, in non-interactive mode")
+	flags.BoolVar(&af.ShowItemCount, "show-item-count", false, "Show number of items in each directory in non-interactive mode")
+	flags.BoolVar(&af.ShowPercentBars, "show-percent-bars", false, "Show share of each item in size of its parent directory as percentage and bar in non-interactive mode")
+	flags.BoolVar(&af.ShowRootPercent, "show-root-percent", false, "Show share of each item in the total size of the analyzed directory in non-interactive mode")
+	flags.BoolVar(&af.ShowFullPath, "show-full-path", false, "Show full paths of items without indentation and the dir prefix in non-interactive mode")
+	flags.BoolVar(&af.ShowRelativePath, "show-relative-path", false, "Show paths of items relative to the analyzed directory without indentation and the dir prefix in non-interactive mode")
+	flags.StringVar(&af.DirMarker, "dir-marker", "prefix", "How to mark names of directories: with leading slash (prefix), trailing slash (suffix) or not at all (none) in non-interactive mode")
+	flags.IntVar(&af.NameWidth, "name-width", 0, "Maximal width of printed names, longer ones are shortened by ellipsis, in non-interactive mode (0 means unlimited)")
+	flags.StringVar(&af.NameTruncation, "truncate", "end", "Where to shorten too long names (end, middle) in non-interactive mode")
+	flags.IntVar(&af.Width, "width", 0, "Width the lines are fitted to by shortening names in non-interactive mode (0 means width of the terminal, unlimited when the output is not a terminal)")
+	flags.BoolVar(&af.ShowMtime, "show-mtime", false, "Show time of last modification of each item in non-interactive mode")
+	flags.BoolVar(&af.ShowDuration, "show-duration", false, "Print how long the analysis took after the results in non-interactive mode")
+	flags.BoolVar(&af.ShowDepth, "show-depth", false, "Print maximal depth of directories with the deepest one and the longest path found after the results in non-interactive mode")
+	flags.BoolVar(&af.ShowGrowth, "show-growth", false, "Print change of the total size since the last scan of the same path after the results in non-interactive mode")
+	flags.StringVar(&af.GrowthFile, "growth-file", "", "State file totals of analyzed paths are remembered in for --show-growth (default is gdu/growth.json in the user cache dir)")
+	flags.BoolVar(&af.Verbose, "verbose", false, "Print paths ignored during the analysis, items left out of the listing by the filters and retried reads after the results in non-interactive mode")
+	flags.StringVar(&af.TimeFormat, "time-format", "2006-01-02 15:04", "Format of time of last modification (Go time layout) in non-interactive mode")
+	flags.BoolVar(&af.ShowInodes, "show-inodes", false, "Show inode usage of mounted disks in non-interactive mode")
+	flags.BoolVar(&af.ShowAvail, "show-avail", false, "Show space of mounted disks available to unprivileged users (free space without blocks reserved for root) in non-interactive mode")
+	flags.BoolVar(&af.ShowDisksTotal, "show-disks-total", false, "Show total size, used and free space of all listed mounted disks in non-interactive mode")
+	flags.StringSliceVar(&af.DiskColumns, "disk-columns", []string{}, "Columns of mounted disks listing in given order (name, fstype, size, used, free, avail, usage, inodes, mount) in non-interactive mode")
+	flags.BoolVar(&af.DfLayout, "df", false, "List mounted disks in the layout of df -h (Filesystem, Size, Used, Avail, Use%, Mounted on) in non-interactive mode")
+	flags.StringSliceVar(&af.IncludeFsTypes, "include-fstype", []string{}, "Show only mounted disks with given filesystem types (e.g. ext4,xfs) in non-interactive mode")
+	flags.StringVar(&af.SortDisks, "sort-disks", "", "Sort mounted disks by usage, free, size or name (in order given by --sort-order) in non-interactive mode")
+	flags.StringVar(&af.MountPrefix, "mount-prefix", "", "Show only mounted disks with mount point in given path (e.g. /mnt) in non-interactive mode")
+	flags.StringSliceVar(&af.ExcludeFsTypes, "exclude-fstype", []string{}, "Hide mounted disks with given filesystem types (e.g. tmpfs,squashfs) in non-interactive mode")
+	flags.BoolVarP(&af.Summarize, "summarize", "s", false, "Print only the total in non-interactive mode")
+	flags.BoolVar(&af.NoTotal, "no-total", false, "Do not print the total (and grand total of several dirs) after listing of items in non-interactive mode")
+	flags.BoolVar(&af.RawBytes, "raw-bytes", false, "Show sizes as plain number of bytes in non-interactive mode")
+	flags.StringVar(&af.GroupDigits, "group-digits", "", "Group digits of raw byte sizes and item counts by given thousands separator (e.g. ',' or 'locale' for the separator of the current locale) in non-interactive mode")
+	flags.StringVar(&af.Unit, "unit", "auto", "Show all sizes in given unit (B, K, M, G, T, binary or decimal by --si) instead of choosing it by magnitude in non-interactive mode")
+	flags.IntVar(&af.SizePrecision, "precision", 1, "Number of decimal places of sizes (0-3) in non-interactive mode")
+	flags.StringVar(&af.MediumSize, "medium-size", "", "Highlight items bigger than given size (e.g. 100M) with orange color in non-interactive mode")
+	flags.StringVar(&af.LargeSize, "large-size", "", "Highlight items bigger than given size (e.g. 1G) with red color in non-interactive mode")
+	flags.Float64Var(&af.UsageWarning, "usage-warning", 0, "Highlight used percentage of mounted disks with at least given usage (e.g. 80) with orange color in non-interactive mode")
+	flags.Float64Var(&af.UsageCritical, "usage-critical", 0, "Highlight used percentage of mounted disks with at least given usage (e.g. 95) with red color in non-interactive mode")
+	flags.StringVar(&af.FailOver, "fail-over", "", "Exit with non-zero code after printing the results when the total size exceeds given size (e.g. 100G) in non-interactive mode")
 	flags.BoolVarP(&af.ShowVersion, "version", "v", false, "Print version")
 	flags.BoolVarP(&af.NoColor, "no-color", "c", false, "Do not use colorized output")
+	flags.BoolVar(&af.NoSizeColor, "no-size-color", false, "Do not colorize sizes in non-interactive mode")
+	flags.BoolVar(&af.NoDirColor, "no-dir-color", false, "Do not colorize names of directories in non-interactive mode")
+	flags.BoolVar(&af.NoPercentColor, "no-percent-color", false, "Do not colorize used percentage of mounted disks in non-interactive mode")
+	flags.BoolVar(&af.NoHidden, "no-hidden", false, "Do not show hidden files and directories in non-interactive mode")
+	flags.BoolVar(&af.DirsOnly, "dirs-only", false, "Show only directories (sizes still include the files) in non-interactive mode")
+	flags.BoolVar(&af.FilesOnly, "files-only", false, "Show only files (with paths relative to the analyzed dir) in non-interactive mode")
 	flags.BoolVarP(&af.NonInteractive, "non-interactive", "n", false, "Do not run in interactive mode")
 	flags.BoolVarP(&af.NoProgress, "no-progress", "p", false, "Do not show progress in non-interactive mode")
+	flags.BoolVarP(&af.Quiet, "quiet", "q", false, "Do not print listing of items, progress and totals, only errors, in non-interactive mode (e.g. with --save-scan or --fail-over)")
+	flags.DurationVar(&af.ProgressInterval, "progress-interval", 0, "Refresh interval of progress (e.g. 1s) in non-interactive mode (0 means 100ms, 1s for plain and json progress)")
+	flags.StringVar(&af.ProgressMode, "progress-mode", "auto", "Progress mode (auto, spinner, plain, json) in non-interactive mode (auto uses plain lines when stderr is not a terminal, json prints one object per line for monitoring)")
+	flags.BoolVar(&af.ASCIIOnly, "ascii", false, "Use only ASCII characters for drawing (e.g. of the progress spinner) in non-interactive mode")
+	flags.StringVar(&af.Spinner, "spinner", "", "Characters cycled by the progress spinner (e.g. '|/-\\') in non-interactive mode (empty means braille spinner)")
+	flags.StringVar(&af.ProgressMessage, "progress-message", "", "Template of the progress message with {items}, {size} and {item} placeholders in non-interactive mode")
 	flags.BoolVarP(&af.NoCross, "no-cross", "x", false, "Do not cross filesystem boundaries")
 }
 
@@ -75,6 +167,7 @@ func runE(command *cobra.Command, args []string) error {
 		Args:    args,
 		Istty:   istty,
 		Writer:  os.Stdout,
+		Reader:  os.Stdin,
 		TermApp: termApp,
 		Getter:  device.Getter,
 	}
@@ -8,7 +8,10 @@ type Device struct {
 	MountPoint string
 	Fstype     string
 	Size       int64
-	Free       int64
+	Free       int64 // free space available to unprivileged users
+	Bfree      int64 // free space including blocks reserved for root
+	Inodes     int64
+	InodesFree int64
 }
 
 // DevicesInfoGetter is type for GetDevicesInfo function
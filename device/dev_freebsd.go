@@ -84,6 +84,9 @@ func processMounts(mounts Devices) (Devices, error) {
 
 			mount.Size = int64(info.Bsize) * int64(info.Blocks)
 			mount.Free = int64(info.Bsize) * int64(info.Bavail)
+			mount.Bfree = int64(info.Bsize) * int64(info.Bfree)
+			mount.Inodes = int64(info.Files)
+			mount.InodesFree = int64(info.Ffree)
 
 			devices = append(devices, mount)
 		}
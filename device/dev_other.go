@@ -1,4 +1,4 @@
-// +build windows darwin openbsd netbsd plan9
+// +build darwin openbsd netbsd plan9
 
 package device
 
@@ -0,0 +1,90 @@
+package device
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// WindowsDevicesInfoGetter returns info for Windows drives
+type WindowsDevicesInfoGetter struct{}
+
+// Getter is current instance of DevicesInfoGetter
+var Getter DevicesInfoGetter = WindowsDevicesInfoGetter{}
+
+// GetMounts returns all logical drives (e.g. C:\) with their filesystem types
+func (t WindowsDevicesInfoGetter) GetMounts() (Devices, error) {
+	buf := make([]uint16, 256)
+	n, err := windows.GetLogicalDriveStrings(uint32(len(buf)), &buf[0])
+	if err != nil {
+		return nil, fmt.Errorf("listing drives: %w", err)
+	}
+
+	mounts := Devices{}
+	for _, drive := range splitDriveStrings(buf[:n]) {
+		mounts = append(mounts, &Device{
+			Name:       strings.TrimSuffix(drive, `\`),
+			MountPoint: drive,
+			Fstype:     getFsType(drive),
+		})
+	}
+	return mounts, nil
+}
+
+// GetDevicesInfo returns result of GetMounts with usage info about the drives (by calling GetDiskFreeSpaceEx)
+func (t WindowsDevicesInfoGetter) GetDevicesInfo() (Devices, error) {
+	mounts, err := t.GetMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	devices := Devices{}
+	for _, mount := range mounts {
+		root, err := windows.UTF16PtrFromString(mount.MountPoint)
+		if err != nil {
+			continue
+		}
+
+		var avail, total, free uint64
+		if err := windows.GetDiskFreeSpaceEx(root, &avail, &total, &free); err != nil {
+			// drives without media (e.g. empty card readers) cannot be queried
+			continue
+		}
+
+		mount.Size = int64(total)
+		mount.Free = int64(avail)
+		mount.Bfree = int64(free)
+		devices = append(devices, mount)
+	}
+	return devices, nil
+}
+
+// splitDriveStrings splits null-separated list of drives returned by GetLogicalDriveStrings
+func splitDriveStrings(buf []uint16) []string {
+	drives := make([]string, 0)
+	start := 0
+	for i, c := range buf {
+		if c != 0 {
+			continue
+		}
+		if i > start {
+			drives = append(drives, windows.UTF16ToString(buf[start:i]))
+		}
+		start = i + 1
+	}
+	return drives
+}
+
+func getFsType(drive string) string {
+	root, err := windows.UTF16PtrFromString(drive)
+	if err != nil {
+		return ""
+	}
+
+	fsName := make([]uint16, windows.MAX_PATH+1)
+	if err := windows.GetVolumeInformation(root, nil, 0, nil, nil, nil, &fsName[0], uint32(len(fsName))); err != nil {
+		return ""
+	}
+	return windows.UTF16ToString(fsName)
+}
@@ -7,9 +7,10 @@ require (
 	github.com/gdamore/tcell/v2 v2.2.0
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-isatty v0.0.12
+	github.com/mattn/go-runewidth v0.0.10
 	github.com/rivo/tview v0.0.0-20210217110421-8a8f78a6dd01
 	github.com/spf13/cobra v1.1.3
 	github.com/stretchr/testify v1.6.1
-	golang.org/x/sys v0.0.0-20210220050731-9a76102bfb43 // indirect
-	golang.org/x/term v0.0.0-20210220032956-6a3ed077a48d // indirect
+	golang.org/x/sys v0.0.0-20210220050731-9a76102bfb43
+	golang.org/x/term v0.0.0-20210220032956-6a3ed077a48d
 )
@@ -2,6 +2,7 @@ package testanalyze
 
 import (
 	"errors"
+	"time"
 
 	"github.com/dundee/gdu/v4/analyze"
 )
@@ -14,8 +15,8 @@ func (a *MockedAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnor
 	dir := &analyze.Dir{
 		File: &analyze.File{
 			Name:  "test_dir",
-			Usage: 1e12 + 1,
-			Size:  1e12 + 2,
+			Usage: 1<<40 + 1<<30 + 1<<20 + 1<<10 + 4,
+			Size:  1<<40 + 1<<30 + 1<<20 + 1<<10 + 8,
 		},
 		BasePath:  ".",
 		ItemCount: 12,
@@ -23,8 +24,8 @@ func (a *MockedAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnor
 	file := &analyze.Dir{
 		File: &analyze.File{
 			Name:   "aaa",
-			Usage:  1e12 + 1,
-			Size:   1e12 + 2,
+			Usage:  1<<40 + 1,
+			Size:   1<<40 + 2,
 			Parent: dir,
 		},
 		ItemCount: 5,
@@ -32,8 +33,8 @@ func (a *MockedAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnor
 	file2 := &analyze.Dir{
 		File: &analyze.File{
 			Name:   "bbb",
-			Usage:  1e9 + 1,
-			Size:   1e9 + 2,
+			Usage:  1<<30 + 1,
+			Size:   1<<30 + 2,
 			Parent: dir,
 		},
 		ItemCount: 3,
@@ -41,16 +42,16 @@ func (a *MockedAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnor
 	file3 := &analyze.Dir{
 		File: &analyze.File{
 			Name:   "ccc",
-			Usage:  1e6 + 1,
-			Size:   1e6 + 2,
+			Usage:  1<<20 + 1,
+			Size:   1<<20 + 2,
 			Parent: dir,
 		},
 		ItemCount: 2,
 	}
 	file4 := &analyze.File{
 		Name:   "ddd",
-		Usage:  1e3 + 1,
-		Size:   1e3 + 2,
+		Usage:  1<<10 + 1,
+		Size:   1<<10 + 2,
 		Parent: dir,
 	}
 	dir.Files = analyze.Files{file, file2, file3, file4}
@@ -73,6 +74,27 @@ func (a *MockedAnalyzer) GetDoneChan() chan struct{} {
 // ResetProgress does nothing
 func (a *MockedAnalyzer) ResetProgress() {}
 
+// SetMaxConcurrency does nothing
+func (a *MockedAnalyzer) SetMaxConcurrency(n int) {}
+
+// SetFollowSymlinks does nothing
+func (a *MockedAnalyzer) SetFollowSymlinks(follow bool) {}
+
+// SetDedupHardlinks does nothing
+func (a *MockedAnalyzer) SetDedupHardlinks(dedup bool) {}
+
+// SetCrossFilesystems does nothing
+func (a *MockedAnalyzer) SetCrossFilesystems(cross bool) {}
+
+// SetReadRetries does nothing
+func (a *MockedAnalyzer) SetReadRetries(
+	retries int, backoff time.Duration, retried analyze.ReadRetried, cancel <-chan struct{},
+) {
+}
+
+// SetIgnoreFile does nothing
+func (a *MockedAnalyzer) SetIgnoreFile(ignore analyze.ShouldFileBeIgnored) {}
+
 // RemoveItemFromDirWithErr returns error
 func RemoveItemFromDirWithErr(dir *analyze.Dir, file analyze.Item) error {
 	return errors.New("Failed")
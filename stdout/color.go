@@ -0,0 +1,115 @@
+package stdout
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/dundee/gdu/v4/device"
+	"github.com/fatih/color"
+)
+
+var colorCodeRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// colorsFromEnv returns whether colors should be used based on NO_COLOR (see https://no-color.org)
+// and FORCE_COLOR env variables and whether they are forced to be used
+func colorsFromEnv(useColors bool) (use bool, force bool) {
+	if os.Getenv("NO_COLOR") != "" {
+		return false, false
+	}
+	if value := os.Getenv("FORCE_COLOR"); value != "" && value != "0" {
+		return true, true
+	}
+	return useColors, false
+}
+
+// SetColorSize sets whether sizes should be colored when colors are used
+func (ui *UI) SetColorSize(colorSize bool) {
+	ui.colorSize = colorSize
+}
+
+// SetColorDir sets whether names of dirs should be colored when colors are used
+func (ui *UI) SetColorDir(colorDir bool) {
+	ui.colorDir = colorDir
+}
+
+// SetColorPercent sets whether used percentage of devices should be colored when colors are used
+func (ui *UI) SetColorPercent(colorPercent bool) {
+	ui.colorPercent = colorPercent
+}
+
+// SetSizeThresholds sets sizes from which items are highlighted
+// as medium (orange) or large (red), 0 disables given tier.
+// When no threshold is set, sizes of all items are orange.
+func (ui *UI) SetSizeThresholds(medium, large int64) {
+	ui.mediumSize = medium
+	ui.largeSize = large
+}
+
+// SetUsageThresholds sets percentages of used space from which devices are highlighted
+// as nearly full (orange) or full (red), 0 disables given tier.
+// When no threshold is set, used percentage of all devices is red.
+func (ui *UI) SetUsageThresholds(warning, critical float64) {
+	ui.usageWarning = warning
+	ui.usageCritical = critical
+}
+
+// formatUsedPercent formats used percentage of a device with color based on usage thresholds
+func (ui *UI) formatUsedPercent(dev *device.Device) string {
+	// pseudo filesystems (e.g. tmpfs) can report zero size
+	if dev.Size == 0 {
+		return fmt.Sprintf("%5s", "-")
+	}
+
+	percent := math.Round(getUsedPercent(dev))
+
+	var c *color.Color
+	switch {
+	case !ui.colorPercent:
+		// percentage is printed without color
+	case ui.usageWarning == 0 && ui.usageCritical == 0:
+		c = ui.red
+	case ui.usageCritical > 0 && percent >= ui.usageCritical:
+		c = ui.red
+	case ui.usageWarning > 0 && percent >= ui.usageWarning:
+		c = ui.orange
+	}
+
+	if c == nil {
+		return fmt.Sprintf("%4.f%%", percent)
+	}
+	return padLeft(c.Sprintf("%.f%%", percent), 5)
+}
+
+// formatItemSize formats size of the item with color based on size thresholds
+func (ui *UI) formatItemSize(size int64) string {
+	if !ui.useColors || ui.rawBytes {
+		return ui.formatSize(size)
+	}
+	if !ui.colorSize {
+		return padLeft(ui.formatSizeWithColor(size, nil), ui.sizeWidth())
+	}
+
+	var c *color.Color
+	switch {
+	case ui.mediumSize == 0 && ui.largeSize == 0:
+		c = ui.orange
+	case ui.largeSize > 0 && size >= ui.largeSize:
+		c = ui.red
+	case ui.mediumSize > 0 && size >= ui.mediumSize:
+		c = ui.orange
+	}
+
+	return padLeft(ui.formatSizeWithColor(size, c), ui.sizeWidth())
+}
+
+// padLeft pads string with spaces to given width ignoring color codes
+func padLeft(s string, width int) string {
+	length := len(colorCodeRe.ReplaceAllString(s, ""))
+	if length >= width {
+		return s
+	}
+	return strings.Repeat(" ", width-length) + s
+}
@@ -0,0 +1,296 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/device"
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdev"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	redCode    = "\x1b[31;1m"
+	orangeCode = "\x1b[33;1m"
+)
+
+func createColoredUI(output *bytes.Buffer) *UI {
+	ui := CreateStdoutUI(output, true, false, false)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	return ui
+}
+
+func TestSizeThresholds(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	output := bytes.NewBuffer(nil)
+	ui := createColoredUI(output)
+	ui.SetSizeThresholds(1<<20, 1<<30)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Contains(t, lines[0], redCode+"1.0\x1b[0m TiB")
+	assert.Contains(t, lines[1], redCode+"1.0\x1b[0m GiB")
+	assert.Contains(t, lines[2], orangeCode+"1.0\x1b[0m MiB")
+	assert.Contains(t, lines[3], "   1.0 KiB ddd")
+	assert.NotContains(t, lines[3], "\x1b[3")
+}
+
+func TestSizeThresholdsOnlyLarge(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	output := bytes.NewBuffer(nil)
+	ui := createColoredUI(output)
+	ui.SetSizeThresholds(0, 1<<40)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Contains(t, lines[0], redCode)
+	assert.NotContains(t, lines[1], redCode)
+	assert.NotContains(t, lines[1], orangeCode)
+}
+
+func TestNoSizeThresholds(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	output := bytes.NewBuffer(nil)
+	ui := createColoredUI(output)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	for _, line := range lines[:4] {
+		assert.Contains(t, line, orangeCode)
+	}
+}
+
+func TestPadLeft(t *testing.T) {
+	assert.Equal(t, "  abc", padLeft("abc", 5))
+	assert.Equal(t, "  "+redCode+"abc\x1b[0m", padLeft(redCode+"abc\x1b[0m", 5))
+	assert.Equal(t, "abcdef", padLeft("abcdef", 5))
+}
+
+func TestNoColorEnv(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+
+	output := bytes.NewBuffer(nil)
+	ui := createColoredUI(output)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.False(t, ui.useColors)
+	assert.True(t, color.NoColor)
+	assert.NotContains(t, output.String(), "\x1b[")
+}
+
+func TestNoColorEnvWinsOverForceColor(t *testing.T) {
+	noColor := color.NoColor
+	defer func() { color.NoColor = noColor }()
+
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+	os.Setenv("FORCE_COLOR", "1")
+	defer os.Unsetenv("FORCE_COLOR")
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, true, false, false)
+
+	assert.False(t, ui.useColors)
+	assert.True(t, color.NoColor)
+}
+
+func TestForceColorEnv(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	os.Setenv("FORCE_COLOR", "1")
+	defer os.Unsetenv("FORCE_COLOR")
+
+	output := bytes.NewBuffer(nil)
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.True(t, ui.useColors)
+	assert.False(t, color.NoColor)
+	assert.Contains(t, output.String(), orangeCode)
+}
+
+func TestForceColorEnvDisabled(t *testing.T) {
+	noColor := color.NoColor
+	defer func() { color.NoColor = noColor }()
+
+	os.Setenv("FORCE_COLOR", "0")
+	defer os.Unsetenv("FORCE_COLOR")
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	assert.False(t, ui.useColors)
+	assert.True(t, color.NoColor)
+}
+
+func getDevicesWithUsageMock() testdev.DevicesInfoGetterMock {
+	return testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "/dev/sda1", MountPoint: "/", Size: 100, Free: 5},
+			{Name: "/dev/sdb1", MountPoint: "/a", Size: 100, Free: 6},
+			{Name: "/dev/sdc1", MountPoint: "/b", Size: 100, Free: 20},
+			{Name: "/dev/sdd1", MountPoint: "/c", Size: 100, Free: 21},
+		},
+	}
+}
+
+func TestUsageThresholds(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	output := bytes.NewBuffer(nil)
+	ui := createColoredUI(output)
+	ui.SetUsageThresholds(80, 95)
+	err := ui.ListDevices(getDevicesWithUsageMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Contains(t, lines[1], " "+redCode+"95%\x1b[0m /")
+	assert.Contains(t, lines[2], " "+orangeCode+"94%\x1b[0m /a")
+	assert.Contains(t, lines[3], " "+orangeCode+"80%\x1b[0m /b")
+	assert.True(t, strings.HasSuffix(lines[4], "B   79% /c"))
+}
+
+func TestUsageThresholdsOnlyCritical(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	output := bytes.NewBuffer(nil)
+	ui := createColoredUI(output)
+	ui.SetUsageThresholds(0, 95)
+	err := ui.ListDevices(getDevicesWithUsageMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Contains(t, lines[1], redCode+"95%")
+	assert.True(t, strings.HasSuffix(lines[2], "B   94% /a"))
+	assert.True(t, strings.HasSuffix(lines[3], "B   80% /b"))
+}
+
+func TestNoUsageThresholds(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	output := bytes.NewBuffer(nil)
+	ui := createColoredUI(output)
+	err := ui.ListDevices(getDevicesWithUsageMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Contains(t, lines[4], redCode+"79%")
+}
+
+func TestColorToggles(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	blueCode := "\x1b[34;1m"
+
+	cases := []struct {
+		colorSize, colorDir bool
+	}{
+		{true, true},
+		{false, true},
+		{true, false},
+		{false, false},
+	}
+
+	for _, c := range cases {
+		output := bytes.NewBuffer(nil)
+		ui := createColoredUI(output)
+		ui.SetColorSize(c.colorSize)
+		ui.SetColorDir(c.colorDir)
+		ui.AnalyzePath("test_dir", nil)
+
+		lines := strings.Split(output.String(), "\n")
+		assert.Equal(t, c.colorSize, strings.Contains(lines[0], orangeCode+"1.0\x1b[0m TiB"))
+		assert.Equal(t, c.colorDir, strings.Contains(lines[0], blueCode+"/aaa\x1b[0m"))
+		if !c.colorSize {
+			// layout is kept without colors
+			assert.True(t, strings.HasPrefix(lines[0][1:], "   1.0 TiB "))
+			assert.True(t, strings.HasPrefix(lines[4], "Total: 1.0 TiB"))
+		}
+	}
+}
+
+func TestColorPercentToggle(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	output := bytes.NewBuffer(nil)
+	ui := createColoredUI(output)
+	ui.SetColorPercent(false)
+	ui.SetShowInodes(true)
+	err := ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{{Name: "/dev/sda1", MountPoint: "/", Size: 100, Free: 5, Inodes: 10, InodesFree: 5}},
+	})
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.NotContains(t, lines[1], redCode)
+	assert.Contains(t, lines[1], "  95%")
+	assert.Contains(t, lines[1], "  50%")
+	assert.Contains(t, lines[1], orangeCode+"100\x1b[0m B")
+}
+
+func TestColorSizeToggleInDevices(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	output := bytes.NewBuffer(nil)
+	ui := createColoredUI(output)
+	ui.SetColorSize(false)
+	err := ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{{Name: "/dev/sda1", MountPoint: "/", Size: 100, Free: 5}},
+	})
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   Device      Size      Used      Free Used% Mount point", lines[0])
+	assert.Equal(t, "/dev/sda1     100 B      95 B       5 B   "+redCode+"95%\x1b[0m /", lines[1])
+}
+
+func TestDirMarkerWithColor(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	blueCode := "\x1b[34;1m"
+
+	output := bytes.NewBuffer(nil)
+	ui := createColoredUI(output)
+	ui.SetDirMarker("suffix")
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Contains(t, lines[0], blueCode+"aaa/\x1b[0m")
+	assert.NotContains(t, lines[3], blueCode)
+}
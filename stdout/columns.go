@@ -0,0 +1,152 @@
+package stdout
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dundee/gdu/v4/device"
+)
+
+// deviceColumn is a column of the listing of devices
+type deviceColumn struct {
+	header    string
+	width     int  // values are padded to the width from left, 0 means no padding
+	alignLeft bool // values are padded from right instead
+	value     func(dev *device.Device) string
+}
+
+// deviceColumnNames are names of columns of the listing of devices in the default order
+var deviceColumnNames = []string{"name", "fstype", "size", "used", "free", "avail", "usage", "inodes", "mount"}
+
+// SetDeviceColumns sets which columns (name, fstype, size, used, free, avail, usage, inodes, mount)
+// are printed by ListDevices and in which order.
+// Empty list means the default columns, with avail and inodes columns only if enabled by SetShowAvail and SetShowInodes.
+func (ui *UI) SetDeviceColumns(columns []string) error {
+	for _, column := range columns {
+		if !contains(deviceColumnNames, column) {
+			return fmt.Errorf("unknown device column: %s", column)
+		}
+	}
+	ui.deviceColumns = columns
+	return nil
+}
+
+// getDeviceColumnNames returns names of columns which should be printed
+func (ui *UI) getDeviceColumnNames() []string {
+	if len(ui.deviceColumns) > 0 {
+		return ui.deviceColumns
+	}
+
+	columns := []string{"name", "size", "used", "free"}
+	if ui.showAvail {
+		columns = append(columns, "avail")
+	}
+	columns = append(columns, "usage")
+	if ui.showInodes {
+		columns = append(columns, "inodes")
+	}
+	return append(columns, "mount")
+}
+
+// createDeviceColumns returns printed columns with widths fitting values of given devices
+func (ui *UI) createDeviceColumns(devices device.Devices) []deviceColumn {
+	sizeLength := ui.sizeWidth()
+	if ui.rawBytes {
+		sizeLength = ui.rawBytesWidth()
+	}
+	inodesLength := inodesColumnLength(devices)
+
+	names := ui.getDeviceColumnNames()
+	// free space is split into blocks reserved for root and the available rest only when both are shown
+	free := func(dev *device.Device) int64 { return dev.Free }
+	if contains(names, "avail") {
+		free = func(dev *device.Device) int64 { return dev.Bfree }
+	}
+
+	columns := make([]deviceColumn, 0)
+	for _, name := range names {
+		switch name {
+		case "name":
+			columns = append(columns, deviceColumn{
+				header: "Device",
+				width: maxInt(maxLength(
+					devices,
+					func(dev *device.Device) string { return dev.Name },
+				), len("Devices")),
+				value: func(dev *device.Device) string { return dev.Name },
+			})
+		case "fstype":
+			columns = append(columns, deviceColumn{
+				header: "Type",
+				width: maxInt(maxLength(
+					devices,
+					func(dev *device.Device) string { return dev.Fstype },
+				), len("Type")),
+				value: func(dev *device.Device) string { return dev.Fstype },
+			})
+		case "size":
+			columns = append(columns, ui.createSizeColumn("Size", sizeLength, func(dev *device.Device) int64 { return dev.Size }))
+		case "used":
+			columns = append(columns, ui.createSizeColumn("Used", sizeLength, func(dev *device.Device) int64 { return dev.Size - free(dev) }))
+		case "free":
+			columns = append(columns, ui.createSizeColumn("Free", sizeLength, free))
+		case "avail":
+			columns = append(columns, ui.createSizeColumn("Avail", sizeLength, func(dev *device.Device) int64 { return dev.Free }))
+		case "usage":
+			columns = append(columns, deviceColumn{header: "Used%", width: 5, value: ui.formatUsedPercent})
+		case "inodes":
+			columns = append(columns,
+				createInodesColumn("Inodes", inodesLength, func(dev *device.Device) int64 { return dev.Inodes }),
+				createInodesColumn("IUsed", inodesLength, func(dev *device.Device) int64 { return dev.Inodes - dev.InodesFree }),
+				createInodesColumn("IFree", inodesLength, func(dev *device.Device) int64 { return dev.InodesFree }),
+				deviceColumn{header: "IUse%", width: 5, value: ui.formatInodesUsedPercent},
+			)
+		case "mount":
+			columns = append(columns, deviceColumn{
+				header: "Mount point",
+				width: maxInt(maxLength(
+					devices,
+					func(dev *device.Device) string { return dev.MountPoint },
+				), len("Mount point")),
+				alignLeft: true,
+				value:     func(dev *device.Device) string { return dev.MountPoint },
+			})
+		}
+	}
+	return columns
+}
+
+func (ui *UI) createSizeColumn(header string, width int, size func(dev *device.Device) int64) deviceColumn {
+	return deviceColumn{
+		header: header,
+		width:  width,
+		value:  func(dev *device.Device) string { return ui.formatSize(size(dev)) },
+	}
+}
+
+func createInodesColumn(header string, width int, count func(dev *device.Device) int64) deviceColumn {
+	return deviceColumn{
+		header: header,
+		width:  width,
+		value:  func(dev *device.Device) string { return strconv.FormatInt(count(dev), 10) },
+	}
+}
+
+// printDeviceRow prints values (or headers if dev is nil) of the columns separated by space
+func (ui *UI) printDeviceRow(columns []deviceColumn, dev *device.Device) {
+	cells := make([]string, 0, len(columns))
+	for _, column := range columns {
+		value := column.header
+		if dev != nil {
+			value = column.value(dev)
+		}
+		if column.alignLeft {
+			cells = append(cells, fmt.Sprintf("%-*s", column.width, value))
+		} else {
+			cells = append(cells, padLeft(value, column.width))
+		}
+	}
+	// padding of the last column (or missing mount point of the total row) is not printed
+	fmt.Fprintln(ui.output, strings.TrimRight(strings.Join(cells, " "), " "))
+}
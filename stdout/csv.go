@@ -0,0 +1,32 @@
+package stdout
+
+import (
+	"encoding/csv"
+	"strconv"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// printCSV writes one row for each top-level item of the dirs (under one header)
+func (ui *UI) printCSV(dirs ...*analyze.Dir) error {
+	w := csv.NewWriter(ui.output)
+
+	w.Write([]string{"path", "size", "usage", "is_dir", "item_count"})
+
+	for _, dir := range dirs {
+		files, _ := ui.selectFiles(dir.Files)
+
+		for _, file := range files {
+			w.Write([]string{
+				file.GetPath(),
+				strconv.FormatInt(file.GetSize(), 10),
+				strconv.FormatInt(file.GetUsage(), 10),
+				strconv.FormatBool(file.IsDir()),
+				strconv.Itoa(file.GetItemCount()),
+			})
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,77 @@
+package stdout
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzePathCSV(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, true, false, false)
+	ui.SetOutputFormat(CSVOutput)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	assert.True(t, color.NoColor)
+	assert.NotContains(t, output.String(), "\x1b[")
+
+	rows, err := csv.NewReader(output).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 5)
+	assert.Equal(t, []string{"path", "size", "usage", "is_dir", "item_count"}, rows[0])
+	assert.Equal(t, []string{"test_dir/aaa", "1099511627778", "1099511627777", "true", "5"}, rows[1])
+	assert.Equal(t, []string{"test_dir/ddd", "1026", "1025", "false", "1"}, rows[4])
+}
+
+func TestAnalyzePathCSVQuoting(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/with,comma", []byte("abc"), 0644)
+	os.WriteFile(`test_dir/with "quotes"`, []byte("ab"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(CSVOutput)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	abspath, _ := filepath.Abs("test_dir")
+	assert.Contains(t, output.String(), `"`+abspath+`/with,comma",3,`)
+	assert.Contains(t, output.String(), `"`+abspath+`/with ""quotes""",2,`)
+
+	rows, err := csv.NewReader(output).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 4)
+	assert.Equal(t, abspath+"/nested", rows[1][0])
+	assert.Equal(t, abspath+"/with,comma", rows[2][0])
+	assert.Equal(t, abspath+`/with "quotes"`, rows[3][0])
+}
+
+func TestAnalyzePathCSVWithMaxEntries(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetOutputFormat(CSVOutput)
+	ui.SetMaxEntries(1)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	rows, err := csv.NewReader(output).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "test_dir/aaa", rows[1][0])
+}
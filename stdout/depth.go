@@ -0,0 +1,59 @@
+package stdout
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetShowDepth sets whether the maximal depth of dirs and the longest full path found in the analyzed tree
+// should be printed after the results (in text format only), e.g. to detect runaway recursive structures
+func (ui *UI) SetShowDepth(showDepth bool) {
+	ui.showDepth = showDepth
+}
+
+// findDeepestDir returns the most nested dir in the tree and its depth below the dir
+// (the dir itself if it contains no dirs), the first one by path is returned of equally deep dirs
+func findDeepestDir(dir *analyze.Dir) (*analyze.Dir, int) {
+	deepest, maxDepth := dir, 0
+	for _, item := range dir.Files {
+		subdir, ok := item.(*analyze.Dir)
+		if !ok {
+			continue
+		}
+		found, depth := findDeepestDir(subdir)
+		depth++
+		if depth > maxDepth || depth == maxDepth && found.GetPath() < deepest.GetPath() {
+			deepest, maxDepth = found, depth
+		}
+	}
+	return deepest, maxDepth
+}
+
+// findLongestPath returns the longest full path of item in the tree and its length in characters
+func findLongestPath(item analyze.Item) (string, int) {
+	longest := item.GetPath()
+	maxLength := utf8.RuneCountInString(longest)
+	if dir, ok := item.(*analyze.Dir); ok {
+		for _, file := range dir.Files {
+			path, length := findLongestPath(file)
+			if length > maxLength || length == maxLength && path < longest {
+				longest, maxLength = path, length
+			}
+		}
+	}
+	return longest, maxLength
+}
+
+// printDepth prints the maximal depth of dirs in the tree with the deepest dir and the longest path
+func (ui *UI) printDepth(dir *analyze.Dir) {
+	if !ui.showDepth || !ui.printsText() {
+		return
+	}
+
+	deepest, depth := findDeepestDir(dir)
+	fmt.Fprintf(ui.output, "Max depth: %d (%s)\n", depth, deepest.GetPath())
+	path, length := findLongestPath(dir)
+	fmt.Fprintf(ui.output, "Longest path: %d characters (%s)\n", length, path)
+}
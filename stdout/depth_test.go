@@ -0,0 +1,50 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShowDepth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/a/b/c/d/e", os.ModePerm)
+	os.MkdirAll("test_dir/x/y/z/w/v", os.ModePerm)
+	os.WriteFile("test_dir/nested/subnested/file_with_a_very_long_name", []byte("x"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetShowDepth(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	abs, _ := filepath.Abs("test_dir")
+	longest := abs + "/nested/subnested/file_with_a_very_long_name"
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, "Max depth: 5 ("+abs+"/a/b/c/d/e)", lines[len(lines)-2])
+	assert.Equal(t, "Longest path: "+strconv.Itoa(len(longest))+" characters ("+longest+")", lines[len(lines)-1])
+}
+
+func TestShowDepthOfFlatDir(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowDepth(true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, "Max depth: 1 (test_dir/aaa)", lines[len(lines)-2])
+	assert.Equal(t, "Longest path: 12 characters (test_dir/aaa)", lines[len(lines)-1])
+}
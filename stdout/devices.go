@@ -0,0 +1,146 @@
+package stdout
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dundee/gdu/v4/device"
+)
+
+// SetDevicesInfoGetter sets getter of devices listed by ListStoredDevices (device.Getter by default)
+func (ui *UI) SetDevicesInfoGetter(getter device.DevicesInfoGetter) {
+	ui.devicesGetter = getter
+}
+
+// ListStoredDevices lists devices returned by the getter set by SetDevicesInfoGetter the same way as ListDevices
+func (ui *UI) ListStoredDevices() error {
+	return ui.ListDevices(ui.devicesGetter)
+}
+
+// SetShowDevicesTotal sets whether a row with sums of sizes of all listed devices should be printed
+// after the devices (in text format only)
+func (ui *UI) SetShowDevicesTotal(show bool) {
+	ui.showDevicesTotal = show
+}
+
+// getDevicesTotal returns device named "Total" with sizes and inodes summed over given devices
+func getDevicesTotal(devices device.Devices) *device.Device {
+	total := &device.Device{Name: "Total"}
+	for _, dev := range devices {
+		total.Size += dev.Size
+		total.Free += dev.Free
+		total.Bfree += dev.Bfree
+		total.Inodes += dev.Inodes
+		total.InodesFree += dev.InodesFree
+	}
+	return total
+}
+
+// SetShowAvail sets whether space available to unprivileged users should be listed in a separate Avail column.
+// Used and Free columns then include blocks reserved for root (as in df), so that used and free space add up to the size.
+// Used% stays the share of the size not available to unprivileged users.
+func (ui *UI) SetShowAvail(show bool) {
+	ui.showAvail = show
+}
+
+// SetIncludeFsTypes sets that only devices with one of given filesystem types are listed (e.g. "ext4", "xfs")
+func (ui *UI) SetIncludeFsTypes(fsTypes []string) {
+	ui.includeFsTypes = fsTypes
+}
+
+// SetExcludeFsTypes sets that devices with any of given filesystem types are not listed (e.g. "tmpfs")
+func (ui *UI) SetExcludeFsTypes(fsTypes []string) {
+	ui.excludeFsTypes = fsTypes
+}
+
+// SetMountPrefix sets that only devices mounted in given path or below it are listed (e.g. "/mnt")
+func (ui *UI) SetMountPrefix(prefix string) {
+	ui.mountPrefix = prefix
+}
+
+var deviceSortKeys = []string{"usage", "free", "size", "name"}
+
+// SetDeviceSorting sets key (usage, free, size or name) and order (asc or desc) for sorting of listed devices.
+// Devices are listed in the order given by the system if no key is set.
+func (ui *UI) SetDeviceSorting(sortBy string, sortOrder string) error {
+	if !contains(deviceSortKeys, sortBy) {
+		return fmt.Errorf("unknown device sort key: %s", sortBy)
+	}
+	if !contains(sortOrders, sortOrder) {
+		return fmt.Errorf("unknown sort order: %s", sortOrder)
+	}
+
+	ui.deviceSortBy = sortBy
+	ui.deviceSortOrder = sortOrder
+	return nil
+}
+
+// filterDevices returns devices which should be listed
+func (ui *UI) filterDevices(devices device.Devices) device.Devices {
+	filtered := make(device.Devices, 0, len(devices))
+	for _, dev := range devices {
+		if ui.shouldDeviceBeListed(dev) {
+			filtered = append(filtered, dev)
+		}
+	}
+	return filtered
+}
+
+func (ui *UI) shouldDeviceBeListed(dev *device.Device) bool {
+	if len(ui.includeFsTypes) > 0 && !containsFsType(ui.includeFsTypes, dev.Fstype) {
+		return false
+	}
+	if containsFsType(ui.excludeFsTypes, dev.Fstype) {
+		return false
+	}
+	return ui.matchesMountPrefix(dev.MountPoint)
+}
+
+// matchesMountPrefix returns true if the mount point is the prefix path or lies below it.
+// Both slashes and backslashes (used by Windows drives like C:\) separate parts of the path.
+func (ui *UI) matchesMountPrefix(mountPoint string) bool {
+	if ui.mountPrefix == "" {
+		return true
+	}
+	prefix := strings.TrimRight(ui.mountPrefix, `/\`)
+	if mountPoint == prefix {
+		return true
+	}
+	return strings.HasPrefix(mountPoint, prefix+"/") || strings.HasPrefix(mountPoint, prefix+`\`)
+}
+
+func containsFsType(fsTypes []string, fsType string) bool {
+	for _, t := range fsTypes {
+		if strings.EqualFold(t, fsType) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortDevices sorts devices in place by current device sort settings
+func (ui *UI) sortDevices(devices device.Devices) {
+	if ui.deviceSortBy == "" {
+		return
+	}
+
+	var less func(a, b *device.Device) bool
+	switch ui.deviceSortBy {
+	case "usage":
+		less = func(a, b *device.Device) bool { return getUsedPercent(a) < getUsedPercent(b) }
+	case "free":
+		less = func(a, b *device.Device) bool { return a.Free < b.Free }
+	case "size":
+		less = func(a, b *device.Device) bool { return a.Size < b.Size }
+	default:
+		less = func(a, b *device.Device) bool { return a.Name < b.Name }
+	}
+
+	sort.SliceStable(devices, func(i, j int) bool {
+		if ui.deviceSortOrder == "asc" {
+			return less(devices[i], devices[j])
+		}
+		return less(devices[j], devices[i])
+	})
+}
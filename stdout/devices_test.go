@@ -0,0 +1,379 @@
+package stdout
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/device"
+	"github.com/dundee/gdu/v4/internal/testdev"
+	"github.com/stretchr/testify/assert"
+)
+
+func getDevicesWithFsTypesMock() testdev.DevicesInfoGetterMock {
+	return testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "/dev/sda1", MountPoint: "/", Fstype: "ext4", Size: 1 << 30, Free: 1 << 29},
+			{Name: "tmpfs", MountPoint: "/tmp", Fstype: "tmpfs", Size: 1 << 20, Free: 1 << 20},
+			{Name: "/dev/sdb1", MountPoint: "/mnt/data", Fstype: "xfs", Size: 1 << 30, Free: 1 << 30},
+			{Name: "/dev/loop0", MountPoint: "/mnt/image", Fstype: "squashfs", Size: 1 << 20, Free: 0},
+		},
+	}
+}
+
+func getListedDevices(output string) []string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	res := make([]string, 0, len(lines))
+	for _, line := range lines[1:] {
+		res = append(res, strings.Fields(line)[0])
+	}
+	return res
+}
+
+func TestListStoredDevices(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetDevicesInfoGetter(getDevicesWithFsTypesMock())
+	err := ui.ListStoredDevices()
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"/dev/sda1", "tmpfs", "/dev/sdb1", "/dev/loop0"}, getListedDevices(output.String()))
+}
+
+func TestListDevicesIgnoresStoredGetter(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetDevicesInfoGetter(getDevicesWithFsTypesMock())
+	err := ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "/dev/sdc1", MountPoint: "/srv", Size: 1 << 30, Free: 1 << 29},
+		},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"/dev/sdc1"}, getListedDevices(output.String()))
+}
+
+func TestListDevicesIncludeFsTypes(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetIncludeFsTypes([]string{"ext4", "XFS"})
+	err := ui.ListDevices(getDevicesWithFsTypesMock())
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"/dev/sda1", "/dev/sdb1"}, getListedDevices(output.String()))
+}
+
+func TestListDevicesExcludeFsTypes(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetExcludeFsTypes([]string{"tmpfs", "squashfs"})
+	err := ui.ListDevices(getDevicesWithFsTypesMock())
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"/dev/sda1", "/dev/sdb1"}, getListedDevices(output.String()))
+}
+
+func TestListDevicesIncludeAndExcludeFsTypes(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetIncludeFsTypes([]string{"ext4", "tmpfs"})
+	ui.SetExcludeFsTypes([]string{"tmpfs"})
+	err := ui.ListDevices(getDevicesWithFsTypesMock())
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"/dev/sda1"}, getListedDevices(output.String()))
+}
+
+func TestListDevicesWithoutFsTypeFilter(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	err := ui.ListDevices(getDevicesWithFsTypesMock())
+	assert.Nil(t, err)
+
+	assert.Equal(
+		t,
+		[]string{"/dev/sda1", "tmpfs", "/dev/sdb1", "/dev/loop0"},
+		getListedDevices(output.String()),
+	)
+}
+
+func getWindowsDrivesMock() testdev.DevicesInfoGetterMock {
+	return testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "C:", MountPoint: `C:\`, Fstype: "NTFS", Size: 1 << 40, Free: 1 << 39, Bfree: 1 << 39},
+			{Name: "D:", MountPoint: `D:\`, Fstype: "FAT32", Size: 1 << 30, Free: 1 << 28, Bfree: 1 << 28},
+		},
+	}
+}
+
+func TestListWindowsDrives(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	err := ui.ListDevices(getWindowsDrivesMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, " Device      Size      Used      Free Used% Mount point", lines[0])
+	assert.Equal(t, "     C:   1.0 TiB 512.0 GiB 512.0 GiB   50% C:\\", lines[1])
+	assert.Equal(t, "     D:   1.0 GiB 768.0 MiB 256.0 MiB   75% D:\\", lines[2])
+}
+
+func TestListWindowsDrivesWithMountPrefix(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetMountPrefix(`D:\`)
+	err := ui.ListDevices(getWindowsDrivesMock())
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"D:"}, getListedDevices(output.String()))
+}
+
+func TestListDevicesWithMountPrefix(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetMountPrefix("/mnt/")
+	err := ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "/dev/mapper/very-long-name", MountPoint: "/", Size: 1 << 30, Free: 1 << 29},
+			{Name: "/dev/sdb1", MountPoint: "/mnt", Size: 1 << 30, Free: 1 << 30},
+			{Name: "/dev/sdc1", MountPoint: "/mnt/data", Size: 1 << 30, Free: 1 << 29},
+			{Name: "/dev/sdd1", MountPoint: "/mntx", Size: 1 << 30, Free: 1 << 29},
+		},
+	})
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   Device      Size      Used      Free Used% Mount point", lines[0])
+	assert.Equal(t, "/dev/sdb1   1.0 GiB       0 B   1.0 GiB    0% /mnt", lines[1])
+	assert.Equal(t, "/dev/sdc1   1.0 GiB 512.0 MiB 512.0 MiB   50% /mnt/data", lines[2])
+	assert.Equal(t, "", lines[3])
+}
+
+func getDevicesForSortingMock() testdev.DevicesInfoGetterMock {
+	return testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "/dev/sdb1", MountPoint: "/mnt/b", Size: 4000, Free: 1000},
+			{Name: "/dev/sda1", MountPoint: "/", Size: 1000, Free: 100},
+			{Name: "/dev/sdc1", MountPoint: "/mnt/c", Size: 2000, Free: 1500},
+		},
+	}
+}
+
+func TestListDevicesSorted(t *testing.T) {
+	cases := []struct {
+		sortBy   string
+		order    string
+		expected []string
+	}{
+		{"usage", "desc", []string{"/dev/sda1", "/dev/sdb1", "/dev/sdc1"}},
+		{"usage", "asc", []string{"/dev/sdc1", "/dev/sdb1", "/dev/sda1"}},
+		{"free", "desc", []string{"/dev/sdc1", "/dev/sdb1", "/dev/sda1"}},
+		{"free", "asc", []string{"/dev/sda1", "/dev/sdb1", "/dev/sdc1"}},
+		{"size", "desc", []string{"/dev/sdb1", "/dev/sdc1", "/dev/sda1"}},
+		{"size", "asc", []string{"/dev/sda1", "/dev/sdc1", "/dev/sdb1"}},
+		{"name", "desc", []string{"/dev/sdc1", "/dev/sdb1", "/dev/sda1"}},
+		{"name", "asc", []string{"/dev/sda1", "/dev/sdb1", "/dev/sdc1"}},
+	}
+
+	for _, c := range cases {
+		output := bytes.NewBuffer(nil)
+
+		ui := CreateStdoutUI(output, false, false, false)
+		err := ui.SetDeviceSorting(c.sortBy, c.order)
+		assert.Nil(t, err)
+		err = ui.ListDevices(getDevicesForSortingMock())
+		assert.Nil(t, err)
+
+		assert.Equal(t, c.expected, getListedDevices(output.String()), c.sortBy+" "+c.order)
+	}
+}
+
+func TestListDevicesUnsorted(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	err := ui.ListDevices(getDevicesForSortingMock())
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"/dev/sdb1", "/dev/sda1", "/dev/sdc1"}, getListedDevices(output.String()))
+}
+
+func TestSetDeviceSortingWithWrongValues(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	err := ui.SetDeviceSorting("itemCount", "asc")
+	assert.Equal(t, "unknown device sort key: itemCount", err.Error())
+
+	err = ui.SetDeviceSorting("usage", "up")
+	assert.Equal(t, "unknown sort order: up", err.Error())
+}
+
+func TestListDevicesWithZeroSize(t *testing.T) {
+	getter := testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "/dev/sda1", MountPoint: "/", Size: 1 << 30, Free: 1 << 29},
+			{Name: "none", MountPoint: "/sys/fs/pstore", Size: 0, Free: 0},
+		},
+	}
+
+	output := bytes.NewBuffer(nil)
+	ui := CreateStdoutUI(output, false, false, false)
+	err := ui.ListDevices(getter)
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "     none       0 B       0 B       0 B     - /sys/fs/pstore", lines[2])
+	assert.NotContains(t, output.String(), "NaN")
+	assert.NotContains(t, output.String(), "Inf")
+
+	output.Reset()
+	ui.SetOutputFormat(JSONOutput)
+	err = ui.ListDevices(getter)
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), `"usedPercent": 0`)
+	assert.NotContains(t, output.String(), "NaN")
+}
+
+func getDevicesWithReservedBlocksMock() testdev.DevicesInfoGetterMock {
+	return testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "/dev/sda1", MountPoint: "/", Size: 1 << 30, Free: 1 << 28, Bfree: 1 << 29},
+		},
+	}
+}
+
+func TestListDevicesWithAvail(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowAvail(true)
+	err := ui.ListDevices(getDevicesWithReservedBlocksMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   Device      Size      Used      Free     Avail Used% Mount point", lines[0])
+	assert.Equal(t, "/dev/sda1   1.0 GiB 512.0 MiB 512.0 MiB 256.0 MiB   75% /", lines[1])
+}
+
+func TestListDevicesWithAvailAndInodes(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowAvail(true)
+	ui.SetShowInodes(true)
+	err := ui.ListDevices(getDevicesWithReservedBlocksMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   Device      Size      Used      Free     Avail Used% Inodes  IUsed  IFree IUse% Mount point", lines[0])
+	assert.Equal(t, "/dev/sda1   1.0 GiB 512.0 MiB 512.0 MiB 256.0 MiB   75%      0      0      0     - /", lines[1])
+}
+
+func TestListDevicesWithoutAvail(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	err := ui.ListDevices(getDevicesWithReservedBlocksMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   Device      Size      Used      Free Used% Mount point", lines[0])
+	assert.Equal(t, "/dev/sda1   1.0 GiB 768.0 MiB 256.0 MiB   75% /", lines[1])
+}
+
+func TestListDevicesWithTotal(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowDevicesTotal(true)
+	ui.SetRawBytes(true)
+	err := ui.ListDevices(getDevicesWithFsTypesMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 6)
+
+	var size, used, free int64
+	for _, line := range lines[1:5] {
+		fields := strings.Fields(line)
+		size += parseInt(t, fields[1])
+		used += parseInt(t, fields[2])
+		free += parseInt(t, fields[3])
+	}
+
+	total := strings.Fields(lines[5])
+	assert.Equal(t, []string{"Total", "2149580800", "537919488", "1611661312", "25%"}, total)
+	assert.Equal(t, size, parseInt(t, total[1]))
+	assert.Equal(t, used, parseInt(t, total[2]))
+	assert.Equal(t, free, parseInt(t, total[3]))
+}
+
+func TestListDevicesWithZeroTotal(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowDevicesTotal(true)
+	err := ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "none", MountPoint: "/empty"},
+		},
+	})
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "  Total       0 B       0 B       0 B     -", lines[2])
+}
+
+func parseInt(t *testing.T, s string) int64 {
+	n, err := strconv.ParseInt(s, 10, 64)
+	assert.Nil(t, err)
+	return n
+}
+
+func TestListDevicesWithColumns(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowAvail(true)
+	err := ui.SetDeviceColumns([]string{"mount", "used", "free", "usage"})
+	assert.Nil(t, err)
+	err = ui.ListDevices(getDevicesWithFsTypesMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "Mount point      Used      Free Used%", lines[0])
+	assert.Equal(t, "/           512.0 MiB 512.0 MiB   50%", lines[1])
+	assert.Equal(t, "/tmp              0 B   1.0 MiB    0%", lines[2])
+	assert.Equal(t, "/mnt/image    1.0 MiB       0 B  100%", lines[4])
+}
+
+func TestListDevicesWithFsTypeColumn(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	err := ui.SetDeviceColumns([]string{"name", "fstype", "size", "inodes"})
+	assert.Nil(t, err)
+	err = ui.ListDevices(getDevicesWithFsTypesMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    Device     Type      Size Inodes  IUsed  IFree IUse%", lines[0])
+	assert.Equal(t, "/dev/loop0 squashfs   1.0 MiB      0      0      0     -", lines[4])
+}
+
+func TestSetUnknownDeviceColumn(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetDeviceColumns([]string{"name", "xxx"})
+	assert.Equal(t, "unknown device column: xxx", err.Error())
+}
@@ -0,0 +1,100 @@
+package stdout
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dundee/gdu/v4/device"
+)
+
+// dfUnits are suffixes of sizes printed by df -h
+const dfUnits = "KMGTPE"
+
+// SetDfLayout sets whether devices should be listed by ListDevices in the layout of `df -h`
+// (Filesystem, Size, Used, Avail, Use% and Mounted on columns with the same headers, widths, alignment
+// and size format) so that gdu can replace df in scripts. Colors and columns set by SetDeviceColumns are not used.
+func (ui *UI) SetDfLayout(dfLayout bool) {
+	ui.dfLayout = dfLayout
+}
+
+// formatDfSize formats the size as df -h does: powers of 1024 with one-letter units,
+// rounded up to one decimal place for values below 10 and to whole numbers otherwise
+func formatDfSize(size int64) string {
+	if size < 1024 {
+		return strconv.FormatInt(size, 10)
+	}
+
+	value := float64(size)
+	unit := -1
+	for {
+		value /= 1024
+		unit++
+
+		rounded := math.Ceil(value)
+		if value < 10 {
+			rounded = math.Ceil(value*10) / 10
+		}
+		if rounded >= 1024 && unit < len(dfUnits)-1 {
+			continue
+		}
+		if rounded < 10 {
+			return fmt.Sprintf("%.1f%c", rounded, dfUnits[unit])
+		}
+		return fmt.Sprintf("%.0f%c", rounded, dfUnits[unit])
+	}
+}
+
+// formatDfUsedPercent returns used space as percentage of space available to unprivileged users
+// rounded up as df does, "-" for devices without any such space
+func formatDfUsedPercent(dev *device.Device) string {
+	used := dev.Size - dev.Bfree
+	if used+dev.Free <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", math.Ceil(float64(used)*100/float64(used+dev.Free)))
+}
+
+// printDevicesDf prints the devices in the layout of df -h (with the total row of df --total)
+func (ui *UI) printDevicesDf(devices device.Devices) {
+	rows := [][]string{{"Filesystem", "Size", "Used", "Avail", "Use%", "Mounted on"}}
+	for _, dev := range devices {
+		rows = append(rows, []string{
+			dev.Name,
+			formatDfSize(dev.Size),
+			formatDfSize(dev.Size - dev.Bfree),
+			formatDfSize(dev.Free),
+			formatDfUsedPercent(dev),
+			dev.MountPoint,
+		})
+	}
+	if ui.showDevicesTotal {
+		total := getDevicesTotal(devices)
+		rows = append(rows, []string{
+			"total",
+			formatDfSize(total.Size),
+			formatDfSize(total.Size - total.Bfree),
+			formatDfSize(total.Free),
+			formatDfUsedPercent(total),
+			"-",
+		})
+	}
+
+	// minimal widths of the columns used by df
+	widths := []int{14, 5, 5, 5, 4}
+	for _, row := range rows {
+		for i := range widths {
+			widths[i] = maxInt(widths[i], len(row[i]))
+		}
+	}
+
+	for _, row := range rows {
+		cells := []string{fmt.Sprintf("%-*s", widths[0], row[0])}
+		for i := 1; i < len(widths); i++ {
+			cells = append(cells, padLeft(row[i], widths[i]))
+		}
+		cells = append(cells, row[5])
+		fmt.Fprintln(ui.output, strings.Join(cells, " "))
+	}
+}
@@ -0,0 +1,70 @@
+package stdout
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/device"
+	"github.com/dundee/gdu/v4/internal/testdev"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDfLayout(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, true, false, false)
+	ui.SetDfLayout(true)
+	err := ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "/dev/sda1", MountPoint: "/", Size: 20 << 30, Free: 14 << 30, Bfree: 15 << 30},
+			{Name: "tmpfs", MountPoint: "/dev/shm", Size: 6 << 30, Free: 6 << 30, Bfree: 6 << 30},
+			{Name: "/dev/mapper/vg-home", MountPoint: "/home", Size: 1 << 40, Free: 1 << 38, Bfree: 1 << 38},
+		},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, strings.Join([]string{
+		"Filesystem           Size  Used Avail Use% Mounted on",
+		"/dev/sda1             20G  5.0G   14G  27% /",
+		"tmpfs                6.0G     0  6.0G   0% /dev/shm",
+		"/dev/mapper/vg-home  1.0T  768G  256G  75% /home",
+	}, "\n")+"\n", output.String())
+}
+
+func TestDfLayoutWithTotal(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetDfLayout(true)
+	ui.SetShowDevicesTotal(true)
+	err := ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "/dev/sda1", MountPoint: "/", Size: 1 << 30, Free: 1 << 28, Bfree: 1 << 29},
+			{Name: "/dev/sdb1", MountPoint: "/srv", Size: 1 << 30, Free: 1 << 30, Bfree: 1 << 30},
+		},
+	})
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, "Filesystem      Size  Used Avail Use% Mounted on", lines[0])
+	assert.Equal(t, "/dev/sda1       1.0G  512M  256M  67% /", lines[1])
+	assert.Equal(t, "total           2.0G  512M  1.3G  29% -", lines[3])
+}
+
+func TestFormatDfSize(t *testing.T) {
+	assert.Equal(t, "0", formatDfSize(0))
+	assert.Equal(t, "1023", formatDfSize(1023))
+	assert.Equal(t, "1.0K", formatDfSize(1024))
+	assert.Equal(t, "1.1K", formatDfSize(1025))
+	assert.Equal(t, "10K", formatDfSize(10*1024))
+	assert.Equal(t, "11K", formatDfSize(10*1024+1))
+	assert.Equal(t, "1.0M", formatDfSize(1024*1024-1))
+	assert.Equal(t, "1.5G", formatDfSize(3<<29))
+}
+
+func TestFormatDfUsedPercent(t *testing.T) {
+	assert.Equal(t, "-", formatDfUsedPercent(&device.Device{}))
+	assert.Equal(t, "1%", formatDfUsedPercent(&device.Device{Size: 1000, Free: 999, Bfree: 999}))
+	assert.Equal(t, "100%", formatDfUsedPercent(&device.Device{Size: 1000, Free: 0, Bfree: 50}))
+}
@@ -0,0 +1,122 @@
+package stdout
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+const diffSizeWidth = 11
+
+type diffEntry struct {
+	name    string
+	mark    rune
+	oldItem analyze.Item
+	newItem analyze.Item
+	oldSize int64
+	newSize int64
+}
+
+func (e *diffEntry) delta() int64 {
+	return e.newSize - e.oldSize
+}
+
+// DiffScans prints items whose size differs between the old and the new dir tree to w
+// followed by change of the total size.
+// Added items are marked by '+', removed by '-' and changed by '~'.
+// Only dirs present in both trees are descended into.
+func (ui *UI) DiffScans(oldDir, newDir *analyze.Dir, w io.Writer) error {
+	ui.printDiff(w, oldDir.Files, newDir.Files, "")
+
+	oldSize, newSize := ui.getSize(oldDir), ui.getSize(newDir)
+	fmt.Fprintf(
+		w,
+		"Total: %s -> %s (%s)\n",
+		ui.formatSize(oldSize),
+		ui.formatSize(newSize),
+		ui.formatSizeDelta(newSize-oldSize),
+	)
+	return nil
+}
+
+// DiffPath analyzes given path and prints its difference from the old dir tree
+func (ui *UI) DiffPath(path string, oldDir *analyze.Dir) error {
+	dir, err := ui.analyzePath(context.Background(), path)
+	if err != nil {
+		return err
+	}
+	return ui.DiffScans(oldDir, dir, ui.output)
+}
+
+func (ui *UI) printDiff(w io.Writer, oldFiles, newFiles analyze.Files, prefix string) {
+	for _, entry := range ui.diffEntries(oldFiles, newFiles) {
+		fmt.Fprintf(
+			w,
+			"%c %s %s %s%s\n",
+			entry.mark,
+			padLeft(ui.formatSizeDelta(entry.delta()), diffSizeWidth),
+			padLeft(ui.formatSize(entry.newSize), diffSizeWidth),
+			prefix,
+			entry.name,
+		)
+
+		oldSubdir, oldOk := entry.oldItem.(*analyze.Dir)
+		newSubdir, newOk := entry.newItem.(*analyze.Dir)
+		if oldOk && newOk {
+			ui.printDiff(w, oldSubdir.Files, newSubdir.Files, prefix+entry.name+"/")
+		}
+	}
+}
+
+// diffEntries returns changed items sorted by the size of the change
+func (ui *UI) diffEntries(oldFiles, newFiles analyze.Files) []*diffEntry {
+	oldItems := make(map[string]analyze.Item, len(oldFiles))
+	for _, item := range oldFiles {
+		oldItems[item.GetName()] = item
+	}
+
+	entries := make([]*diffEntry, 0)
+	for _, item := range newFiles {
+		entry := &diffEntry{name: item.GetName(), mark: '+', newItem: item, newSize: ui.getSize(item)}
+		if oldItem, ok := oldItems[entry.name]; ok {
+			delete(oldItems, entry.name)
+			entry.mark = '~'
+			entry.oldItem = oldItem
+			entry.oldSize = ui.getSize(oldItem)
+			if entry.delta() == 0 {
+				continue
+			}
+		}
+		entries = append(entries, entry)
+	}
+	for name, item := range oldItems {
+		entries = append(entries, &diffEntry{name: name, mark: '-', oldItem: item, oldSize: ui.getSize(item)})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		di, dj := abs(entries[i].delta()), abs(entries[j].delta())
+		if di != dj {
+			return di > dj
+		}
+		return entries[i].name < entries[j].name
+	})
+	return entries
+}
+
+// formatSizeDelta formats size difference with explicit sign
+func (ui *UI) formatSizeDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + ui.formatSize(-delta)
+	}
+	return "+" + ui.formatSize(delta)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,109 @@
+package stdout
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func createDiffDir(sizes map[string]int64) *analyze.Dir {
+	root := &analyze.Dir{
+		File:     &analyze.File{Name: "root"},
+		BasePath: "/",
+	}
+	dirs := map[string]*analyze.Dir{"": root}
+
+	for _, path := range []string{"var", "var/log", "var/log/syslog", "var/cache", "home", "home/big", "tmp", "tmp/new"} {
+		size, ok := sizes[path]
+		if !ok {
+			continue
+		}
+
+		parentPath, name := "", path
+		if i := strings.LastIndex(path, "/"); i >= 0 {
+			parentPath, name = path[:i], path[i+1:]
+		}
+		parent := dirs[parentPath]
+
+		file := &analyze.File{Name: name, Size: size, Usage: size, Parent: parent}
+		if size < 0 {
+			file.Size, file.Usage = 0, 0
+			dir := &analyze.Dir{File: file}
+			dirs[path] = dir
+			parent.Files.Append(dir)
+		} else {
+			parent.Files.Append(file)
+		}
+	}
+
+	root.UpdateStats(make(analyze.AlreadyCountedHardlinks))
+	return root
+}
+
+func TestDiffScans(t *testing.T) {
+	oldDir := createDiffDir(map[string]int64{
+		"var":            -1,
+		"var/log":        -1,
+		"var/log/syslog": 1000,
+		"var/cache":      5000,
+		"home":           -1,
+		"home/big":       1 << 20,
+	})
+	newDir := createDiffDir(map[string]int64{
+		"var":            -1,
+		"var/log":        -1,
+		"var/log/syslog": 3048,
+		"home":           -1,
+		"home/big":       1 << 20,
+		"tmp":            -1,
+		"tmp/new":        100,
+	})
+
+	output := bytes.NewBuffer(nil)
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, true)
+	ui.SetRawBytes(true)
+	err := ui.DiffScans(oldDir, newDir, output)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Equal(t, []string{
+		"+       +4196        4196 tmp",
+		"~       -2952       11240 var",
+		"-       -5000           0 var/cache",
+		"~       +2048        7144 var/log",
+		"~       +2048        3048 var/log/syslog",
+		"Total: 1070960 -> 1072204 (+1244)",
+	}, lines)
+}
+
+func TestDiffScansWithoutChanges(t *testing.T) {
+	sizes := map[string]int64{"var": -1, "var/cache": 5000}
+
+	output := bytes.NewBuffer(nil)
+	ui := CreateStdoutUI(output, false, false, false)
+	err := ui.DiffScans(createDiffDir(sizes), createDiffDir(sizes), output)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Total: 12.9 KiB -> 12.9 KiB (+0 B)\n", output.String())
+}
+
+func TestDiffPath(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRawBytes(true)
+
+	oldDir := &analyze.Dir{File: &analyze.File{Name: "test_dir"}, BasePath: "."}
+	err := ui.DiffPath("test_dir", oldDir)
+	assert.Nil(t, err)
+
+	assert.Contains(t, output.String(), "+")
+	assert.Contains(t, output.String(), " nested\n")
+	assert.NotContains(t, output.String(), "nested/")
+}
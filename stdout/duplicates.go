@@ -0,0 +1,163 @@
+package stdout
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// duplicateGroup is a set of files with the same content
+type duplicateGroup struct {
+	size  int64
+	paths []string
+}
+
+// linkID identifies the file all hard links of it point to
+type linkID struct {
+	dev uint64
+	ino uint64
+}
+
+// wasted returns size which would be freed by keeping only one of the files
+func (g *duplicateGroup) wasted() int64 {
+	return g.size * int64(len(g.paths)-1)
+}
+
+// SetDuplicates sets that only groups of files with the same content found anywhere in the analyzed tree
+// are printed with size wasted by the copies instead of the listing of the dir.
+// Files are grouped by size first and only files sharing their size with another file are read and hashed.
+// Hard links of the same file and symlinks (even if they are followed) are not reported as duplicates.
+func (ui *UI) SetDuplicates(duplicates bool) {
+	ui.duplicates = duplicates
+}
+
+// findDuplicates returns groups of files with the same content sorted by wasted size
+// and paths of files which could not be read
+func (ui *UI) findDuplicates(dir *analyze.Dir) ([]*duplicateGroup, []string) {
+	bySize := make(map[int64]analyze.Files)
+	ui.collectFilesBySize(dir, bySize)
+
+	groups := make([]*duplicateGroup, 0)
+	unreadable := make([]string, 0)
+	seenLinks := make(map[linkID]struct{})
+	for size, files := range bySize {
+		if len(files) < 2 {
+			continue
+		}
+
+		byHash := make(map[[sha256.Size]byte][]string)
+		for _, file := range files {
+			path := file.GetPath()
+
+			// followed symlinks have info of their targets, so they are recognized here
+			info, err := os.Lstat(path)
+			if err != nil {
+				unreadable = append(unreadable, path)
+				continue
+			}
+			if info.Mode()&os.ModeSymlink != 0 {
+				continue
+			}
+			if id, ok := getLinkID(info); ok {
+				if _, seen := seenLinks[id]; seen {
+					continue
+				}
+				seenLinks[id] = struct{}{}
+			}
+
+			hash, err := ui.fileHasher(path)
+			if err != nil {
+				unreadable = append(unreadable, path)
+				continue
+			}
+			byHash[hash] = append(byHash[hash], path)
+		}
+
+		for _, paths := range byHash {
+			if len(paths) > 1 {
+				sort.Strings(paths)
+				groups = append(groups, &duplicateGroup{size: size, paths: paths})
+			}
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].wasted() != groups[j].wasted() {
+			return groups[i].wasted() > groups[j].wasted()
+		}
+		return groups[i].paths[0] < groups[j].paths[0]
+	})
+	sort.Strings(unreadable)
+	return groups, unreadable
+}
+
+// collectFilesBySize adds all non-empty files of the dir tree to the map by their apparent size,
+// symlinks and sockets (flagged by '@') are skipped
+func (ui *UI) collectFilesBySize(dir *analyze.Dir, bySize map[int64]analyze.Files) {
+	for _, item := range dir.Files {
+		if !ui.showHidden && isHidden(item.GetName()) {
+			continue
+		}
+		switch item := item.(type) {
+		case *analyze.Dir:
+			ui.collectFilesBySize(item, bySize)
+		case *analyze.File:
+			if item.Size > 0 && item.Flag != '@' {
+				bySize[item.Size] = append(bySize[item.Size], item)
+			}
+		}
+	}
+}
+
+func hashFile(path string) ([sha256.Size]byte, error) {
+	var hash [sha256.Size]byte
+
+	f, err := os.Open(path)
+	if err != nil {
+		return hash, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return hash, err
+	}
+	copy(hash[:], h.Sum(nil))
+	return hash, nil
+}
+
+// printDuplicates prints groups of duplicate files, files which could not be compared and the total wasted size
+func (ui *UI) printDuplicates(dir *analyze.Dir) {
+	var total int64
+
+	groups, unreadable := ui.findDuplicates(dir)
+	for _, group := range groups {
+		fmt.Fprintf(ui.output,
+			"%d copies of %s, %s wasted:\n",
+			len(group.paths),
+			ui.formatSize(group.size),
+			ui.formatSize(group.wasted()))
+		for _, path := range group.paths {
+			fmt.Fprintf(ui.output, "  %s\n", path)
+		}
+		total += group.wasted()
+	}
+
+	if len(unreadable) > 0 {
+		fmt.Fprintln(ui.output, "Not compared, could not be read:")
+		for _, path := range unreadable {
+			fmt.Fprintf(ui.output, "  %s\n", path)
+		}
+	}
+
+	if ui.showTotal {
+		fmt.Fprintf(ui.output,
+			"Total wasted: %s in %s of duplicates\n",
+			ui.formatSize(total),
+			pluralize(len(groups), "group", "groups"))
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package stdout
+
+import "os"
+
+func getLinkID(info os.FileInfo) (linkID, bool) {
+	return linkID{}, false
+}
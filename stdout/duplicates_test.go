@@ -0,0 +1,144 @@
+package stdout
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func createDuplicateFiles() {
+	os.WriteFile("test_dir/copy1", []byte("abcdef"), 0644)
+	os.WriteFile("test_dir/nested/copy2", []byte("abcdef"), 0644)
+	os.WriteFile("test_dir/unique", []byte("ghijkl"), 0644)
+	os.WriteFile("test_dir/big1", bytes.Repeat([]byte("x"), 100), 0644)
+	os.WriteFile("test_dir/nested/subnested/big2", bytes.Repeat([]byte("x"), 100), 0644)
+	os.WriteFile("test_dir/big3", bytes.Repeat([]byte("x"), 100), 0644)
+	os.WriteFile("test_dir/other", bytes.Repeat([]byte("y"), 99), 0644)
+	os.WriteFile("test_dir/empty1", nil, 0644)
+	os.WriteFile("test_dir/empty2", nil, 0644)
+}
+
+func TestDuplicates(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+	createDuplicateFiles()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetDuplicates(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Len(t, lines, 8)
+	assert.Equal(t, "3 copies of 100 B, 200 B wasted:", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], "/test_dir/big1"))
+	assert.True(t, strings.HasSuffix(lines[2], "/test_dir/big3"))
+	assert.True(t, strings.HasSuffix(lines[3], "/test_dir/nested/subnested/big2"))
+	assert.Equal(t, "2 copies of 6 B, 6 B wasted:", lines[4])
+	assert.True(t, strings.HasSuffix(lines[5], "/test_dir/copy1"))
+	assert.True(t, strings.HasSuffix(lines[6], "/test_dir/nested/copy2"))
+	assert.Equal(t, "Total wasted: 206 B in 2 groups of duplicates", lines[7])
+}
+
+func TestDuplicatesSingleGroup(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/copy1", []byte("abcdef"), 0644)
+	os.WriteFile("test_dir/nested/copy2", []byte("abcdef"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetDuplicates(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "Total wasted: 6 B in 1 group of duplicates\n")
+}
+
+func TestDuplicatesSkipHardlinks(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/file", []byte("abcdef"), 0644)
+	os.Link("test_dir/file", "test_dir/link")
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetDuplicates(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Equal(t, "Total wasted: 0 B in 0 groups of duplicates\n", output.String())
+}
+
+func TestDuplicatesHashOnlyFilesOfSameSize(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+	createDuplicateFiles()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetDuplicates(true)
+	hashed := make([]string, 0)
+	ui.fileHasher = func(path string) ([sha256.Size]byte, error) {
+		hashed = append(hashed, filepath.Base(path))
+		return hashFile(path)
+	}
+	ui.AnalyzePath("test_dir", nil)
+
+	sort.Strings(hashed)
+	assert.Equal(t, []string{"big1", "big2", "big3", "copy1", "copy2", "unique"}, hashed)
+}
+
+func TestDuplicatesSkipFollowedSymlinks(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/file", []byte("abcdef"), 0644)
+	os.Symlink("file", "test_dir/symlink")
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetDuplicates(true)
+	ui.SetFollowSymlinks(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Equal(t, "Total wasted: 0 B in 0 groups of duplicates\n", output.String())
+}
+
+func TestDuplicatesReportUnreadableFiles(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+	createDuplicateFiles()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetDuplicates(true)
+	ui.fileHasher = func(path string) ([sha256.Size]byte, error) {
+		if filepath.Base(path) == "big3" {
+			return [sha256.Size]byte{}, errors.New("permission denied")
+		}
+		return hashFile(path)
+	}
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Len(t, lines, 9)
+	assert.Equal(t, "2 copies of 100 B, 100 B wasted:", lines[0])
+	assert.Equal(t, "Not compared, could not be read:", lines[6])
+	assert.True(t, strings.HasSuffix(lines[7], "/test_dir/big3"))
+	assert.Equal(t, "Total wasted: 106 B in 2 groups of duplicates", lines[8])
+}
@@ -0,0 +1,17 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package stdout
+
+import (
+	"os"
+	"syscall"
+)
+
+// getLinkID returns device and inode of the file if the file has more hard links
+func getLinkID(info os.FileInfo) (linkID, bool) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Nlink > 1 {
+		return linkID{dev: uint64(stat.Dev), ino: stat.Ino}, true
+	}
+	return linkID{}, false
+}
@@ -0,0 +1,28 @@
+package stdout
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetShowDuration sets whether wall-clock time of the analysis should be printed after the results
+// (in text format only)
+func (ui *UI) SetShowDuration(showDuration bool) {
+	ui.showDuration = showDuration
+}
+
+// printScanDuration prints how long the last analysis took
+func (ui *UI) printScanDuration() {
+	if !ui.showDuration || !ui.printsText() {
+		return
+	}
+	fmt.Fprintf(ui.output, "Scan duration: %s\n", formatDuration(ui.scanDuration))
+}
+
+// formatDuration rounds the duration to milliseconds (or seconds for durations over a minute)
+func formatDuration(d time.Duration) string {
+	if d >= time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
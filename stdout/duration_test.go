@@ -0,0 +1,74 @@
+package stdout
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+// sleepingAnalyzer takes given time to analyze the dir
+type sleepingAnalyzer struct {
+	testanalyze.MockedAnalyzer
+	duration time.Duration
+}
+
+func (a *sleepingAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnored) *analyze.Dir {
+	time.Sleep(a.duration)
+	return a.MockedAnalyzer.AnalyzeDir(path, ignore)
+}
+
+func TestShowDuration(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowDuration(true)
+	ui.analyzer = &sleepingAnalyzer{duration: 50 * time.Millisecond}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	last := lines[len(lines)-1]
+	assert.Regexp(t, regexp.MustCompile(`^Scan duration: [0-9.]+m?s$`), last)
+
+	duration, err := time.ParseDuration(strings.TrimPrefix(last, "Scan duration: "))
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, int64(duration), int64(50*time.Millisecond))
+}
+
+func TestShowDurationInJSON(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowDuration(true)
+	ui.SetOutputFormat(JSONOutput)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.NotContains(t, output.String(), "Scan duration")
+}
+
+func TestDurationNotShownByDefault(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.NotContains(t, output.String(), "Scan duration")
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "1.235s", formatDuration(1234567*time.Microsecond))
+	assert.Equal(t, "12ms", formatDuration(12345*time.Microsecond))
+	assert.Equal(t, "2m5s", formatDuration(2*time.Minute+4567*time.Millisecond))
+}
@@ -0,0 +1,93 @@
+package stdout
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetEmptyDirs sets that only paths of empty dirs found anywhere in the analyzed tree
+// are printed followed by their count instead of the listing of the dir
+func (ui *UI) SetEmptyDirs(emptyDirs bool) {
+	ui.emptyDirs = emptyDirs
+}
+
+// SetZeroSizeDirsAsEmpty sets whether dirs containing only files of zero size (and such dirs)
+// should be reported as empty too. Only the topmost of the nested reported dirs is printed then.
+func (ui *UI) SetZeroSizeDirsAsEmpty(zeroSize bool) {
+	ui.zeroSizeAsEmpty = zeroSize
+}
+
+// isEmptyDir returns true if the dir contains no items (or only items of zero size if zeroSizeAsEmpty is set).
+// Dirs which could not be read and dirs with entries left out of the analysis (e.g. ignored ones) are never empty.
+func (ui *UI) isEmptyDir(dir *analyze.Dir) bool {
+	switch dir.GetFlag() {
+	case '!':
+		return false
+	case 'e':
+		return true
+	}
+	if _, ok := ui.ignoredParents[dir.GetPath()]; ok {
+		return false
+	}
+	// all entries of the dir were left out if it has no items
+	if !ui.zeroSizeAsEmpty || len(dir.Files) == 0 {
+		return false
+	}
+
+	for _, item := range dir.Files {
+		if subdir, ok := item.(*analyze.Dir); ok {
+			if !ui.isEmptyDir(subdir) {
+				return false
+			}
+		} else if item.GetSize() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// findEmptyDirs returns sorted paths of empty dirs in the dir tree (without the dir itself)
+func (ui *UI) findEmptyDirs(dir *analyze.Dir) []string {
+	paths := ui.collectEmptyDirs(dir, make([]string, 0))
+	sort.Strings(paths)
+	return paths
+}
+
+func (ui *UI) collectEmptyDirs(dir *analyze.Dir, paths []string) []string {
+	for _, item := range dir.Files {
+		subdir, ok := item.(*analyze.Dir)
+		if !ok || !ui.showHidden && isHidden(subdir.GetName()) {
+			continue
+		}
+		if !ui.isEmptyDir(subdir) {
+			paths = ui.collectEmptyDirs(subdir, paths)
+		} else if ui.showRelativePath {
+			paths = append(paths, ui.relativePath(subdir))
+		} else {
+			paths = append(paths, subdir.GetPath())
+		}
+	}
+	return paths
+}
+
+// printEmptyDirs prints paths of empty dirs in the dir tree and their count
+func (ui *UI) printEmptyDirs(dir *analyze.Dir) {
+	ui.rootPath = dir.GetPath()
+
+	ui.ignoredParents = make(map[string]struct{}, len(ui.ignoredPaths))
+	for _, path := range ui.ignoredPaths {
+		ui.ignoredParents[filepath.Dir(path)] = struct{}{}
+	}
+
+	paths := ui.findEmptyDirs(dir)
+	for _, path := range paths {
+		fmt.Fprintln(ui.output, path)
+	}
+
+	if ui.showTotal {
+		fmt.Fprintf(ui.output, "Total: %s\n", pluralize(len(paths), "empty dir", "empty dirs"))
+	}
+}
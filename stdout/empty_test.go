@@ -0,0 +1,135 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func createEmptyDirs() {
+	os.MkdirAll("test_dir/empty", 0755)
+	os.MkdirAll("test_dir/nested/empty2", 0755)
+	os.MkdirAll("test_dir/zero/empty3", 0755)
+	os.WriteFile("test_dir/zero/file", nil, 0644)
+	os.MkdirAll("test_dir/.hidden", 0755)
+}
+
+func TestEmptyDirs(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+	createEmptyDirs()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetEmptyDirs(true)
+	ui.SetShowRelativePath(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		".hidden",
+		"empty",
+		"nested/empty2",
+		"zero/empty3",
+		"Total: 4 empty dirs",
+	}, lines)
+}
+
+func TestEmptyDirsWithZeroSize(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+	createEmptyDirs()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetEmptyDirs(true)
+	ui.SetZeroSizeDirsAsEmpty(true)
+	ui.SetShowHidden(false)
+	ui.SetShowRelativePath(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		"empty",
+		"nested/empty2",
+		"zero",
+		"Total: 3 empty dirs",
+	}, lines)
+}
+
+func TestEmptyDirsFullPaths(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetEmptyDirs(true)
+	ui.SetShowTotal(false)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Equal(t, "", output.String())
+
+	os.Mkdir("test_dir/nested/subnested/empty", 0755)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.True(t, strings.HasSuffix(output.String(), "/test_dir/nested/subnested/empty\n"))
+}
+
+func TestEmptyDirsSkipsUnreadableDirs(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+	createEmptyDirs()
+
+	os.Chmod("test_dir/nested/subnested", 0)
+	defer os.Chmod("test_dir/nested/subnested", 0755)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.warningOutput = &bytes.Buffer{}
+	ui.SetEmptyDirs(true)
+	ui.SetShowRelativePath(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		".hidden",
+		"empty",
+		"nested/empty2",
+		"zero/empty3",
+		"Total: 4 empty dirs",
+	}, lines)
+}
+
+func TestEmptyDirsSkipsDirsWithIgnoredEntries(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+	createEmptyDirs()
+
+	os.MkdirAll("test_dir/cache/node_modules", 0755)
+	os.WriteFile("test_dir/cache/node_modules/file", []byte("data"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetEmptyDirs(true)
+	ui.SetShowRelativePath(true)
+	ui.SetIgnoreDirPatterns([]string{"*/node_modules"})
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.NotContains(t, output.String(), "cache")
+	assert.Contains(t, output.String(), "Total: 4 empty dirs")
+
+	output.Reset()
+	ui.SetZeroSizeDirsAsEmpty(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.NotContains(t, output.String(), "cache")
+}
@@ -0,0 +1,54 @@
+package stdout
+
+import (
+	"strings"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetIncludeExtensions sets that only files with one of given extensions are printed
+// (e.g. "log", ".tar.gz"). Matching is case-insensitive.
+// Dirs are printed only if they contain at least one matching file.
+func (ui *UI) SetIncludeExtensions(extensions []string) {
+	ui.includeExts = normalizeExtensions(extensions)
+}
+
+// SetExcludeExtensions sets that files with any of given extensions are not printed.
+// Dirs are printed only if they contain at least one file which is not excluded.
+func (ui *UI) SetExcludeExtensions(extensions []string) {
+	ui.excludeExts = normalizeExtensions(extensions)
+}
+
+func normalizeExtensions(extensions []string) []string {
+	res := make([]string, 0, len(extensions))
+	for _, ext := range extensions {
+		ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+		if ext != "" {
+			res = append(res, "."+ext)
+		}
+	}
+	return res
+}
+
+func (ui *UI) filtersExtensions() bool {
+	return len(ui.includeExts) > 0 || len(ui.excludeExts) > 0
+}
+
+// matchesExtensions returns true if the file matches the extension filters,
+// dirs are matched by their files by matchesFilters
+func (ui *UI) matchesExtensions(file analyze.Item) bool {
+	name := strings.ToLower(file.GetName())
+	if len(ui.includeExts) > 0 && !hasAnySuffix(name, ui.includeExts) {
+		return false
+	}
+	return !hasAnySuffix(name, ui.excludeExts)
+}
+
+func hasAnySuffix(name string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
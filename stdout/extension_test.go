@@ -0,0 +1,108 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func createExtensionsTestDir() func() {
+	fin := testdir.CreateTestDir()
+	os.MkdirAll("test_dir/logs", os.ModePerm)
+	os.WriteFile("test_dir/logs/app.LOG", []byte("log"), 0644)
+	os.WriteFile("test_dir/logs/old.log.gz", []byte("gz"), 0644)
+	os.WriteFile("test_dir/backup.tar.gz", []byte("tar"), 0644)
+	os.WriteFile("test_dir/image.iso", []byte("iso"), 0644)
+	return fin
+}
+
+func analyzeWithExtensions(include, exclude []string) string {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetIncludeExtensions(include)
+	ui.SetExcludeExtensions(exclude)
+	ui.AnalyzePath("test_dir", nil)
+	return output.String()
+}
+
+func TestIncludeExtensions(t *testing.T) {
+	fin := createExtensionsTestDir()
+	defer fin()
+
+	output := analyzeWithExtensions([]string{".log", "tar.gz"}, nil)
+
+	assert.Contains(t, output, "/logs\n")
+	assert.Contains(t, output, "app.LOG\n")
+	assert.Contains(t, output, "backup.tar.gz\n")
+	assert.NotContains(t, output, "old.log.gz")
+	assert.NotContains(t, output, "image.iso")
+	// dirs without matching files are hidden
+	assert.NotContains(t, output, "nested")
+}
+
+func TestExcludeExtensions(t *testing.T) {
+	fin := createExtensionsTestDir()
+	defer fin()
+
+	output := analyzeWithExtensions(nil, []string{"ISO", "gz"})
+
+	assert.Contains(t, output, "app.LOG\n")
+	assert.Contains(t, output, "/nested\n")
+	assert.Contains(t, output, "file2\n")
+	assert.NotContains(t, output, "old.log.gz")
+	assert.NotContains(t, output, "backup.tar.gz")
+	assert.NotContains(t, output, "image.iso")
+}
+
+func TestIncludeAndExcludeExtensions(t *testing.T) {
+	fin := createExtensionsTestDir()
+	defer fin()
+
+	output := analyzeWithExtensions([]string{"gz"}, []string{"tar.gz"})
+
+	assert.Contains(t, output, "/logs\n")
+	assert.Contains(t, output, "old.log.gz\n")
+	assert.NotContains(t, output, "backup.tar.gz")
+	assert.NotContains(t, output, "app.LOG")
+	assert.NotContains(t, output, "nested")
+}
+
+func TestExtensionsWithMatchingTotal(t *testing.T) {
+	fin := createExtensionsTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetIncludeExtensions([]string{"gz"})
+	ui.SetTotalMatchingOnly(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "Total of matching files: 5 B, 2 items")
+}
+
+func TestExtensionsWithOlderThan(t *testing.T) {
+	fin := createExtensionsTestDir()
+	defer fin()
+
+	old := time.Now().Add(-48 * time.Hour)
+	os.Chtimes("test_dir/logs/old.log.gz", old, old)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetIncludeExtensions([]string{"log"})
+	ui.SetOlderThan(24 * time.Hour)
+	ui.SetTotalMatchingOnly(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	// the old file and the file with matching extension are different ones
+	assert.NotContains(t, output.String(), "logs")
+	assert.Contains(t, output.String(), "Total of matching files: 0 B, 0 items")
+}
@@ -0,0 +1,107 @@
+package stdout
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// noExtension is the name of the group of files without extension
+const noExtension = "(none)"
+
+// extensionTotal is the total size and count of files with the same extension
+type extensionTotal struct {
+	ext   string
+	size  int64
+	count int
+}
+
+// SetExtensionTotals sets that only total size and count of files grouped by their (lowercased) extension
+// found anywhere in the analyzed tree are printed instead of the listing of the dir.
+// Only the last extension is used (e.g. ".gz" for "backup.tar.gz"), files without any are grouped as "(none)".
+func (ui *UI) SetExtensionTotals(extensionTotals bool) {
+	ui.extensionTotals = extensionTotals
+}
+
+// getExtension returns lowercased extension of the file name or noExtension,
+// dot at the beginning of hidden files does not start the extension
+func getExtension(name string) string {
+	ext := filepath.Ext(name)
+	if ext == "" || ext == "." || ext == name {
+		return noExtension
+	}
+	return strings.ToLower(ext)
+}
+
+// findExtensionTotals returns totals of files by extension sorted by size
+func (ui *UI) findExtensionTotals(dir *analyze.Dir) []*extensionTotal {
+	byExt := make(map[string]*extensionTotal)
+	ui.collectExtensionTotals(dir, byExt)
+
+	totals := make([]*extensionTotal, 0, len(byExt))
+	for _, total := range byExt {
+		totals = append(totals, total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].size != totals[j].size {
+			return totals[i].size > totals[j].size
+		}
+		return totals[i].ext < totals[j].ext
+	})
+	return totals
+}
+
+// collectExtensionTotals adds all files of the dir tree matching the filters to totals of their extensions
+func (ui *UI) collectExtensionTotals(dir *analyze.Dir, byExt map[string]*extensionTotal) {
+	for _, item := range dir.Files {
+		if !ui.showHidden && isHidden(item.GetName()) {
+			continue
+		}
+		if subdir, ok := item.(*analyze.Dir); ok {
+			ui.collectExtensionTotals(subdir, byExt)
+			continue
+		}
+		// other link of the same file has been already counted
+		if item.GetFlag() == 'H' {
+			continue
+		}
+		if !ui.matchesFilters(item, allFilters) ||
+			ui.getSize(item) < ui.minSize {
+			continue
+		}
+
+		ext := getExtension(item.GetName())
+		total, ok := byExt[ext]
+		if !ok {
+			total = &extensionTotal{ext: ext}
+			byExt[ext] = total
+		}
+		total.size += ui.getSize(item)
+		total.count++
+	}
+}
+
+// printExtensionTotals prints total size and count of files of each extension and the total of all files
+func (ui *UI) printExtensionTotals(dir *analyze.Dir) {
+	var (
+		size  int64
+		count int
+	)
+
+	for _, total := range ui.findExtensionTotals(dir) {
+		fmt.Fprintf(ui.output,
+			"%s: %s, %s\n",
+			total.ext,
+			ui.formatSize(total.size),
+			pluralize(total.count, "file", "files"))
+		size += total.size
+		count += total.count
+	}
+
+	if ui.showTotal {
+		fmt.Fprintf(ui.output, "Total: %s, %s\n", ui.formatSize(size), pluralize(count, "file", "files"))
+	}
+}
@@ -0,0 +1,80 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtensionTotals(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/photo.jpg", []byte("aaaaaaaaaa"), 0644)
+	os.WriteFile("test_dir/nested/PHOTO2.JPG", []byte("aaaaa"), 0644)
+	os.WriteFile("test_dir/nested/app.log", []byte("aaa"), 0644)
+	os.WriteFile("test_dir/nested/subnested/backup.tar.gz", []byte("aaaaaaa"), 0644)
+	os.WriteFile("test_dir/.hidden", []byte("a"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetExtensionTotals(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		".jpg: 15 B, 2 files",
+		"(none): 8 B, 3 files",
+		".gz: 7 B, 1 file",
+		".log: 3 B, 1 file",
+		"Total: 33 B, 7 files",
+	}, lines)
+}
+
+func TestExtensionTotalsWithoutHidden(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/.hidden", []byte("a"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetExtensionTotals(true)
+	ui.SetShowHidden(false)
+	ui.SetShowTotal(false)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Equal(t, "(none): 7 B, 2 files\n", output.String())
+}
+
+func TestExtensionTotalsWithHardlinks(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/photo.jpg", []byte("aaaaaaaaaa"), 0644)
+	os.Link("test_dir/photo.jpg", "test_dir/nested/link.jpg")
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetExtensionTotals(true)
+	ui.SetShowTotal(false)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Equal(t, ".jpg: 10 B, 1 file\n(none): 7 B, 2 files\n", output.String())
+}
+
+func TestGetExtension(t *testing.T) {
+	assert.Equal(t, ".jpg", getExtension("IMG.JPG"))
+	assert.Equal(t, ".gz", getExtension("backup.tar.gz"))
+	assert.Equal(t, noExtension, getExtension("Makefile"))
+	assert.Equal(t, noExtension, getExtension(".bashrc"))
+	assert.Equal(t, noExtension, getExtension("file."))
+	assert.Equal(t, ".yml", getExtension(".travis.yml"))
+}
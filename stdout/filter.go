@@ -0,0 +1,233 @@
+package stdout
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetMinSize sets minimal size (or usage) of printed items
+func (ui *UI) SetMinSize(minSize int64) {
+	ui.minSize = minSize
+}
+
+// SetShowHidden sets whether hidden files and directories (starting with a dot) are printed.
+// Hidden items are counted in the total size unless only matching items are counted.
+func (ui *UI) SetShowHidden(showHidden bool) {
+	ui.showHidden = showHidden
+}
+
+// SetDirsOnly sets whether only directories should be printed, files are still counted in sizes of the dirs
+func (ui *UI) SetDirsOnly(dirsOnly bool) {
+	ui.dirsOnly = dirsOnly
+}
+
+// SetFilesOnly sets whether only files should be printed.
+// Files in subdirs are printed with paths relative to the analyzed dir when the tree is printed recursively.
+func (ui *UI) SetFilesOnly(filesOnly bool) {
+	ui.filesOnly = filesOnly
+}
+
+// SetOlderThan sets that only files modified before given duration are printed (0 means no limit).
+// Dirs are printed if they contain at least one such file.
+func (ui *UI) SetOlderThan(olderThan time.Duration) {
+	ui.olderThan = olderThan
+}
+
+// SetNewerThan sets that only files modified within given duration are printed (0 means no limit).
+// Dirs are printed if they contain at least one such file.
+func (ui *UI) SetNewerThan(newerThan time.Duration) {
+	ui.newerThan = newerThan
+}
+
+// SetTotalMatchingOnly sets whether the total should count only files matching
+// the filters of modification time and extension instead of all analyzed items
+func (ui *UI) SetTotalMatchingOnly(matchingTotal bool) {
+	ui.matchingTotal = matchingTotal
+}
+
+// filterFiles returns files which should be printed.
+// Files are returned as they are (without copying) if all of them should be printed.
+func (ui *UI) filterFiles(files analyze.Files) analyze.Files {
+	for i, file := range files {
+		if ui.shouldBePrinted(file) {
+			continue
+		}
+
+		filtered := make(analyze.Files, i, len(files))
+		copy(filtered, files[:i])
+		for _, file := range files[i+1:] {
+			if ui.shouldBePrinted(file) {
+				filtered = append(filtered, file)
+			}
+		}
+		return filtered
+	}
+	return files
+}
+
+func (ui *UI) shouldBePrinted(item analyze.Item) bool {
+	if !ui.showHidden && isHidden(item.GetName()) {
+		return false
+	}
+	if ui.dirsOnly && !item.IsDir() || ui.filesOnly && item.IsDir() {
+		return false
+	}
+	if !ui.matchesFilters(item, allFilters) {
+		return false
+	}
+	return ui.getSize(item) >= ui.minSize
+}
+
+// itemFilter is a set of filters every matching file has to satisfy
+type itemFilter uint8
+
+const (
+	mtimeFilter itemFilter = 1 << iota
+	extensionFilter
+	nameFilter
+
+	allFilters = mtimeFilter | extensionFilter | nameFilter
+)
+
+// dirFilters is a key of the remembered result of matching the dir against the filters
+type dirFilters struct {
+	dir     *analyze.Dir
+	filters itemFilter
+}
+
+func (ui *UI) activeFilters() itemFilter {
+	var filters itemFilter
+	if ui.olderThan > 0 || ui.newerThan > 0 {
+		filters |= mtimeFilter
+	}
+	if ui.filtersExtensions() {
+		filters |= extensionFilter
+	}
+	if ui.namePattern != "" {
+		filters |= nameFilter
+	}
+	return filters
+}
+
+// matchesFilters returns true if the file satisfies all the given filters which are active
+// or the dir contains such file.
+// The name filter is satisfied also by name of any dir the file is in.
+// Results for dirs are remembered so that every dir of the tree is walked only once.
+func (ui *UI) matchesFilters(item analyze.Item, filters itemFilter) bool {
+	filters &= ui.activeFilters()
+	if filters&nameFilter != 0 && ui.matchesName(item) {
+		filters &^= nameFilter
+	}
+	if filters == 0 {
+		return true
+	}
+
+	dir, ok := item.(*analyze.Dir)
+	if !ok {
+		return ui.fileMatchesFilters(item, filters)
+	}
+
+	key := dirFilters{dir: dir, filters: filters}
+	if matches, ok := ui.matchingDirs[key]; ok {
+		return matches
+	}
+
+	matches := false
+	for _, file := range dir.Files {
+		if ui.matchesFilters(file, filters) {
+			matches = true
+			break
+		}
+	}
+
+	if ui.matchingDirs == nil {
+		ui.matchingDirs = make(map[dirFilters]bool)
+	}
+	ui.matchingDirs[key] = matches
+	return matches
+}
+
+func (ui *UI) fileMatchesFilters(file analyze.Item, filters itemFilter) bool {
+	if filters&nameFilter != 0 {
+		// the name has been already checked by matchesFilters
+		return false
+	}
+	if filters&mtimeFilter != 0 && !ui.matchesMtime(file) {
+		return false
+	}
+	return filters&extensionFilter == 0 || ui.matchesExtensions(file)
+}
+
+func (ui *UI) countsMatchingOnly() bool {
+	return ui.matchingTotal || ui.namePattern != ""
+}
+
+// matchesMtime returns true if the file matches the age filters.
+// Dirs are matched by their files by matchesFilters, mtime of a dir is the latest mtime of the contained items.
+func (ui *UI) matchesMtime(file analyze.Item) bool {
+	if ui.olderThan > 0 && file.GetMtime().After(ui.now().Add(-ui.olderThan)) {
+		return false
+	}
+	if ui.newerThan > 0 && file.GetMtime().Before(ui.now().Add(-ui.newerThan)) {
+		return false
+	}
+	return true
+}
+
+// getTotal returns size and item count of the dir,
+// only items matching the filters are counted if matchingTotal or name pattern is set
+func (ui *UI) getTotal(dir *analyze.Dir) (int64, int) {
+	if !ui.countsMatchingOnly() {
+		return ui.getSize(dir), dir.GetItemCount()
+	}
+	return ui.getMatchingTotal(dir, allFilters&ui.activeFilters())
+}
+
+// getMatchingTotal returns size and item count of the items in the dir matching the filters
+// the same way as they are matched for printing.
+// A dir matched by name is counted whole unless it has to satisfy other filters as well.
+func (ui *UI) getMatchingTotal(dir *analyze.Dir, filters itemFilter) (int64, int) {
+	var (
+		size  int64
+		count int
+	)
+	for _, item := range dir.Files {
+		if !ui.showHidden && isHidden(item.GetName()) {
+			continue
+		}
+
+		itemFilters := filters
+		if itemFilters&nameFilter != 0 && ui.matchesName(item) {
+			itemFilters &^= nameFilter
+		}
+
+		if subdir, ok := item.(*analyze.Dir); ok {
+			if itemFilters == 0 {
+				size += ui.getSize(subdir)
+				count += subdir.GetItemCount()
+				continue
+			}
+			subdirSize, subdirCount := ui.getMatchingTotal(subdir, itemFilters)
+			size += subdirSize
+			count += subdirCount
+		} else if itemFilters == 0 || ui.fileMatchesFilters(item, itemFilters) {
+			size += ui.getSize(item)
+			count++
+		}
+	}
+	return size, count
+}
+
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".") && name != "." && name != ".."
+}
+
+// selectFiles returns filtered, sorted and limited files for printing and number of files left out by the limit
+func (ui *UI) selectFiles(files analyze.Files) (analyze.Files, int) {
+	if ui.maxEntries > 0 && ui.sortBy != "none" {
+		return ui.topFiles(files, ui.maxEntries)
+	}
+	return ui.limitFiles(ui.sortedFiles(ui.filterFiles(files)))
+}
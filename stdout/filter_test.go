@@ -0,0 +1,295 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinSize(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetMinSize(1 << 20)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "/aaa")
+	assert.Contains(t, output.String(), "/bbb")
+	assert.Contains(t, output.String(), "/ccc")
+	assert.NotContains(t, output.String(), "ddd")
+
+	// total still contains filtered items
+	assert.Contains(t, output.String(), "Total: 1.0 TiB, 12 items")
+}
+
+func TestMinSizeWithApparentSize(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetMinSize(1<<20 + 2)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	// ccc has apparent size 1<<20 + 2 but usage 1<<20 + 1
+	assert.Contains(t, output.String(), "/ccc")
+	assert.NotContains(t, output.String(), "ddd")
+}
+
+func TestMinSizeNested(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetMinSize(3)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "/subnested")
+	assert.Contains(t, output.String(), " file\n")
+	assert.NotContains(t, output.String(), "file2")
+}
+
+func createHiddenTestDir() func() {
+	fin := testdir.CreateTestDir()
+	os.MkdirAll("test_dir/.hidden_dir", os.ModePerm)
+	os.WriteFile("test_dir/.hidden_dir/file", []byte("xxxxxxxxxx"), 0644)
+	os.WriteFile("test_dir/.hidden_file", []byte("yyyyy"), 0644)
+	os.WriteFile("test_dir/nested/.hidden_nested", []byte("zzz"), 0644)
+	return fin
+}
+
+func TestShowHidden(t *testing.T) {
+	fin := createHiddenTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "/.hidden_dir")
+	assert.Contains(t, output.String(), ".hidden_file")
+	assert.Contains(t, output.String(), ".hidden_nested")
+}
+
+func TestHideHidden(t *testing.T) {
+	fin := createHiddenTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetShowHidden(false)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "/nested")
+	assert.Contains(t, output.String(), "file2")
+	assert.NotContains(t, output.String(), ".hidden")
+
+	// 4096 * 4 (dirs) + 2 + 5 (visible files) + 10 + 5 + 3 (hidden files)
+	assert.Contains(t, output.String(), "Total: "+ui.formatSize(4096*4+25)+", 9 items")
+}
+
+func TestIsHidden(t *testing.T) {
+	assert.True(t, isHidden(".git"))
+	assert.True(t, isHidden(".x"))
+	assert.False(t, isHidden("git"))
+	assert.False(t, isHidden("x.git"))
+	assert.False(t, isHidden("."))
+	assert.False(t, isHidden(".."))
+}
+
+// createAgedTestDir creates test dir with nested/file2 modified two days ago
+// and nested/subnested/file modified two hours ago
+func createAgedTestDir() func() {
+	fin := testdir.CreateTestDir()
+
+	now := time.Now()
+	os.Chtimes("test_dir/nested/file2", now.Add(-48*time.Hour), now.Add(-48*time.Hour))
+	os.Chtimes("test_dir/nested/subnested/file", now.Add(-2*time.Hour), now.Add(-2*time.Hour))
+	return fin
+}
+
+func TestDirsOnly(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetDirsOnly(true)
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		"    8.0 KiB /nested",
+		"    4.0 KiB   /subnested",
+		"Total: 12.0 KiB, 5 items",
+	}, lines)
+}
+
+func TestDirsOnlyWithMockedAnalyzer(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetDirsOnly(true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "   1.0 MiB /ccc")
+	assert.NotContains(t, output.String(), "ddd")
+	assert.Contains(t, output.String(), "Total: 1.0 TiB, 12 items")
+}
+
+func TestFilesOnly(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetFilesOnly(true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   1.0 KiB ddd", lines[0][1:])
+	assert.Equal(t, "Total: 1.0 TiB, 12 items", lines[1])
+}
+
+func TestFilesOnlyRecursive(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/file", []byte("xxx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetFilesOnly(true)
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		"        3 B file",
+		"        2 B nested/file2",
+		"        5 B nested/subnested/file",
+		"Total: 12.0 KiB, 6 items",
+	}, lines)
+}
+
+func TestFilesOnlyWithMaxDepth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetFilesOnly(true)
+	ui.SetMaxDepth(2)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), " nested/file2\n")
+	assert.NotContains(t, output.String(), "subnested")
+}
+
+func TestOlderThan(t *testing.T) {
+	fin := createAgedTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetOlderThan(24 * time.Hour)
+	ui.AnalyzePath("test_dir", nil)
+
+	// nested contains an old file, subnested only the recently modified one
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    8.0 KiB /nested", lines[0])
+	assert.Equal(t, "        2 B   file2", lines[1])
+	assert.Equal(t, "Total: 12.0 KiB, 5 items", lines[2])
+}
+
+func TestOlderThanWithMatchingTotal(t *testing.T) {
+	fin := createAgedTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/fresh", []byte("xxx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOlderThan(24 * time.Hour)
+	ui.SetTotalMatchingOnly(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	// the total counts the same files the listing is made of
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    8.0 KiB /nested", lines[0])
+	assert.Equal(t, "Total of matching files: 2 B, 1 items", lines[1])
+}
+
+func TestOlderThanInDir(t *testing.T) {
+	fin := createAgedTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOlderThan(24 * time.Hour)
+	ui.SetTotalMatchingOnly(true)
+	ui.AnalyzePath("test_dir/nested", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "        2 B file2", lines[0])
+	assert.Equal(t, "Total of matching files: 2 B, 1 items", lines[1])
+}
+
+func TestNewerThan(t *testing.T) {
+	fin := createAgedTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetNewerThan(24 * time.Hour)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "/nested")
+	assert.Contains(t, output.String(), "/subnested")
+	assert.Contains(t, output.String(), " file\n")
+	assert.NotContains(t, output.String(), "file2")
+
+	// total contains all items by default
+	assert.Contains(t, output.String(), "Total: 12.0 KiB, 5 items")
+}
+
+func TestNewerThanWithMatchingTotal(t *testing.T) {
+	fin := createAgedTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetNewerThan(24 * time.Hour)
+	ui.SetTotalMatchingOnly(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "Total of matching files: 5 B, 1 items")
+}
@@ -0,0 +1,46 @@
+package stdout
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// maxFlatFiles is the number of files in the flat listing above which a warning about memory is logged
+const maxFlatFiles = 1000000
+
+// printFlat writes "size<TAB>path" line for each file in the whole tree, sorted from the largest.
+// Only the given number of the largest files is kept in memory if SetMaxEntries is set,
+// otherwise all the files are collected first and a warning is logged for huge trees.
+func (ui *UI) printFlat(dir *analyze.Dir) error {
+	var files analyze.Files
+	if ui.maxEntries > 0 {
+		files = ui.findLargestFiles(dir, ui.maxEntries)
+	} else {
+		files = ui.collectFlatFiles(dir, make(analyze.Files, 0))
+		if len(files) > maxFlatFiles {
+			log.Printf("flat listing holds %d files in memory, limit it by --top", len(files))
+		}
+		sort.Sort(sort.Reverse(&sizeHeap{items: files, getSize: ui.getSize}))
+	}
+
+	for _, file := range files {
+		fmt.Fprintf(ui.output, "%d\t%s\n", ui.getSize(file), tsvEscaper.Replace(file.GetPath()))
+	}
+	return nil
+}
+
+func (ui *UI) collectFlatFiles(dir *analyze.Dir, files analyze.Files) analyze.Files {
+	for _, item := range dir.Files {
+		if subdir, ok := item.(*analyze.Dir); ok {
+			if ui.showHidden || !isHidden(subdir.GetName()) {
+				files = ui.collectFlatFiles(subdir, files)
+			}
+		} else if ui.shouldBePrinted(item) {
+			files = append(files, item)
+		}
+	}
+	return files
+}
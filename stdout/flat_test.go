@@ -0,0 +1,84 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatOutput(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/top", []byte("xxx"), 0644)
+	os.WriteFile("test_dir/nested/subnested/big", []byte("xxxxxxxxxx"), 0644)
+	os.WriteFile("test_dir/nested/same\tsize", []byte("xxx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(FlatOutput)
+	ui.AnalyzePath("test_dir", nil)
+
+	abs, _ := filepath.Abs("test_dir")
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		"10\t" + abs + "/nested/subnested/big",
+		"5\t" + abs + "/nested/subnested/file",
+		"3\t" + abs + `/nested/same\tsize`,
+		"3\t" + abs + "/top",
+		"2\t" + abs + "/nested/file2",
+	}, lines)
+}
+
+func TestFlatOutputWithTop(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/top", []byte("xxx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(FlatOutput)
+	ui.SetMaxEntries(2)
+	ui.SetMinSize(3)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "5\t"))
+	assert.True(t, strings.HasSuffix(lines[1], "/test_dir/top"))
+}
+
+func TestFlatOutputWithoutHidden(t *testing.T) {
+	for _, top := range []int{0, 10} {
+		fin := testdir.CreateTestDir()
+
+		os.MkdirAll("test_dir/.git/objects", os.ModePerm)
+		os.WriteFile("test_dir/.git/objects/pack", []byte("xxxxxxxxxx"), 0644)
+		os.WriteFile("test_dir/.hidden", []byte("xxx"), 0644)
+
+		output := bytes.NewBuffer(nil)
+
+		ui := CreateStdoutUI(output, false, false, true)
+		ui.SetOutputFormat(FlatOutput)
+		ui.SetShowHidden(false)
+		ui.SetMaxEntries(top)
+		ui.AnalyzePath("test_dir", nil)
+
+		abs, _ := filepath.Abs("test_dir")
+		lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+		assert.Equal(t, []string{
+			"5\t" + abs + "/nested/subnested/file",
+			"2\t" + abs + "/nested/file2",
+		}, lines, "top %d", top)
+
+		fin()
+	}
+}
@@ -0,0 +1,64 @@
+package stdout
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OutputFormat defines how results of analysis are printed
+type OutputFormat int
+
+const (
+	// TextOutput prints human readable listing
+	TextOutput OutputFormat = iota
+	// JSONOutput prints analyzed tree as JSON
+	JSONOutput
+	// NcduOutput prints analyzed tree in the ncdu export format
+	NcduOutput
+	// CSVOutput prints top-level items as comma separated values
+	CSVOutput
+	// HTMLOutput prints analyzed tree as HTML report
+	HTMLOutput
+	// MarkdownOutput prints top-level items as Markdown table
+	MarkdownOutput
+	// XMLOutput prints analyzed tree as XML
+	XMLOutput
+	// TSVOutput prints top-level items as tab separated values
+	TSVOutput
+	// NDJSONOutput prints items as JSON objects, one per line
+	NDJSONOutput
+	// FlatOutput prints all files of the tree as size and path separated by tab, sorted by size
+	FlatOutput
+)
+
+var outputFormatNames = map[string]OutputFormat{
+	"text":     TextOutput,
+	"json":     JSONOutput,
+	"ncdu":     NcduOutput,
+	"csv":      CSVOutput,
+	"html":     HTMLOutput,
+	"markdown": MarkdownOutput,
+	"xml":      XMLOutput,
+	"tsv":      TSVOutput,
+	"ndjson":   NDJSONOutput,
+	"flat":     FlatOutput,
+}
+
+// String returns name of the output format
+func (f OutputFormat) String() string {
+	for name, format := range outputFormatNames {
+		if format == f {
+			return name
+		}
+	}
+	return fmt.Sprintf("OutputFormat(%d)", int(f))
+}
+
+// ParseOutputFormat returns output format with given name
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	format, ok := outputFormatNames[strings.ToLower(name)]
+	if !ok {
+		return TextOutput, fmt.Errorf("unknown output format: %s", name)
+	}
+	return format, nil
+}
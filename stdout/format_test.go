@@ -0,0 +1,28 @@
+package stdout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	format, err := ParseOutputFormat("json")
+	assert.Nil(t, err)
+	assert.Equal(t, JSONOutput, format)
+
+	format, err = ParseOutputFormat("TEXT")
+	assert.Nil(t, err)
+	assert.Equal(t, TextOutput, format)
+
+	format, err = ParseOutputFormat("ndjson")
+	assert.Nil(t, err)
+	assert.Equal(t, NDJSONOutput, format)
+
+	format, err = ParseOutputFormat("flat")
+	assert.Nil(t, err)
+	assert.Equal(t, FlatOutput, format)
+
+	_, err = ParseOutputFormat("yaml")
+	assert.Equal(t, "unknown output format: yaml", err.Error())
+}
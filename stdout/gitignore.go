@@ -0,0 +1,181 @@
+package stdout
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+type gitignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// gitignoreMatcher matches paths against .gitignore files found in the analyzed tree.
+// Rules are loaded lazily for each directory and can be used from multiple goroutines.
+type gitignoreMatcher struct {
+	root  string
+	rules map[string][]gitignoreRule
+	mutex sync.Mutex
+}
+
+func newGitignoreMatcher(root string) *gitignoreMatcher {
+	return &gitignoreMatcher{
+		root:  root,
+		rules: make(map[string][]gitignoreRule),
+	}
+}
+
+// isIgnored returns true if path (of dir if isDir is set) is ignored by .gitignore file in any of its parent dirs
+// (up to the root of the matcher). Later and deeper rules take precedence.
+func (m *gitignoreMatcher) isIgnored(path string, isDir bool) bool {
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+
+	var ignored bool
+
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	dir := m.root
+	for i := range parts {
+		relToDir := strings.Join(parts[i:], "/")
+
+		for _, rule := range m.getRules(dir) {
+			if !rule.re.MatchString(relToDir) {
+				continue
+			}
+			if rule.dirOnly && !isDir {
+				continue
+			}
+			ignored = !rule.negate
+		}
+
+		dir = filepath.Join(dir, parts[i])
+	}
+	return ignored
+}
+
+func (m *gitignoreMatcher) getRules(dir string) []gitignoreRule {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if rules, ok := m.rules[dir]; ok {
+		return rules
+	}
+
+	rules := readGitignore(filepath.Join(dir, ".gitignore"))
+	m.rules[dir] = rules
+	return rules
+}
+
+func readGitignore(path string) []gitignoreRule {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	rules := []gitignoreRule{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseGitignoreLine(scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func parseGitignoreLine(line string) (gitignoreRule, bool) {
+	rule := gitignoreRule{}
+
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule, false
+	}
+
+	// patterns containing slash are relative to the dir of .gitignore file,
+	// others match name at any level
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := globToRegex(line)
+	if anchored {
+		expr = "^" + expr + "$"
+	} else {
+		expr = "^(?:.*/)?" + expr + "$"
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return rule, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+func globToRegex(pattern string) string {
+	var res strings.Builder
+	runes := []rune(pattern)
+
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				i++
+				if i+1 < len(runes) && runes[i+1] == '/' {
+					i++
+					res.WriteString("(?:.*/)?")
+				} else {
+					res.WriteString(".*")
+				}
+			} else {
+				res.WriteString("[^/]*")
+			}
+		case '?':
+			res.WriteString("[^/]")
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				res.WriteString(`\[`)
+				continue
+			}
+			class := string(runes[i+1 : end])
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			res.WriteString("[" + class + "]")
+			i = end
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				res.WriteString(regexp.QuoteMeta(string(runes[i])))
+			}
+		default:
+			res.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return res.String()
+}
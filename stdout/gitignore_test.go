@@ -0,0 +1,159 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createGitignoreTestDir() func() {
+	os.MkdirAll("test_dir/build", os.ModePerm)
+	os.MkdirAll("test_dir/src/vendor", os.ModePerm)
+	os.MkdirAll("test_dir/src/logs", os.ModePerm)
+
+	os.WriteFile("test_dir/.gitignore", []byte("# comment\n\n*.log\nbuild/\n/top.tmp\n"), 0644)
+	os.WriteFile("test_dir/src/.gitignore", []byte("!keep.log\nvendor\nlogs/*\n!logs/important.txt\n"), 0644)
+
+	os.WriteFile("test_dir/main.go", []byte("aaa"), 0644)
+	os.WriteFile("test_dir/debug.log", []byte("bbb"), 0644)
+	os.WriteFile("test_dir/top.tmp", []byte("ccc"), 0644)
+	os.WriteFile("test_dir/build/out", []byte("ddd"), 0644)
+	os.WriteFile("test_dir/src/top.tmp", []byte("eee"), 0644)
+	os.WriteFile("test_dir/src/error.log", []byte("fff"), 0644)
+	os.WriteFile("test_dir/src/keep.log", []byte("ggg"), 0644)
+	os.WriteFile("test_dir/src/vendor/lib.go", []byte("hhh"), 0644)
+	os.WriteFile("test_dir/src/logs/today.txt", []byte("iii"), 0644)
+	os.WriteFile("test_dir/src/logs/important.txt", []byte("jjj"), 0644)
+
+	return func() {
+		err := os.RemoveAll("test_dir")
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func TestGitignoreMatcher(t *testing.T) {
+	fin := createGitignoreTestDir()
+	defer fin()
+
+	root, _ := filepath.Abs("test_dir")
+	m := newGitignoreMatcher(root)
+
+	ignored := map[string]bool{
+		"debug.log":          false,
+		"top.tmp":            false,
+		"build":              true,
+		"src/error.log":      false,
+		"src/vendor":         true,
+		"src/logs/today.txt": false,
+	}
+	for path, isDir := range ignored {
+		assert.True(t, m.isIgnored(filepath.Join(root, path), isDir), path)
+	}
+
+	notIgnored := map[string]bool{
+		"":                       true,
+		".gitignore":             false,
+		"main.go":                false,
+		"src":                    true,
+		"src/top.tmp":            false,
+		"src/keep.log":           false,
+		"src/logs":               true,
+		"src/logs/important.txt": false,
+	}
+	for path, isDir := range notIgnored {
+		assert.False(t, m.isIgnored(filepath.Join(root, path), isDir), path)
+	}
+
+	assert.False(t, m.isIgnored("/outside/of/root", true))
+}
+
+func TestGitignoreDirOnlyRule(t *testing.T) {
+	fin := createGitignoreTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/src/build", []byte("kkk"), 0644)
+
+	root, _ := filepath.Abs("test_dir")
+	m := newGitignoreMatcher(root)
+
+	assert.True(t, m.isIgnored(filepath.Join(root, "build"), true))
+	assert.False(t, m.isIgnored(filepath.Join(root, "src/build"), false))
+}
+
+func TestParseGitignoreLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		path    string
+		matches bool
+	}{
+		{"*.o", "a/b/c.o", true},
+		{"*.o", "c.o", true},
+		{"/*.o", "a/c.o", false},
+		{"a/*.o", "a/c.o", true},
+		{"a/*.o", "a/b/c.o", false},
+		{"a/**/c.o", "a/b/d/c.o", true},
+		{"a/**/c.o", "a/c.o", true},
+		{"**/foo", "x/y/foo", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file12.txt", false},
+		{"[abc].txt", "b.txt", true},
+		{"[!abc].txt", "b.txt", false},
+		{"[!abc].txt", "d.txt", true},
+		{`\#file`, "#file", true},
+	}
+
+	for _, tt := range tests {
+		rule, ok := parseGitignoreLine(tt.line)
+		assert.True(t, ok, tt.line)
+		assert.Equal(t, tt.matches, rule.re.MatchString(tt.path), tt.line+" "+tt.path)
+	}
+
+	_, ok := parseGitignoreLine("# comment")
+	assert.False(t, ok)
+	_, ok = parseGitignoreLine("   ")
+	assert.False(t, ok)
+
+	rule, _ := parseGitignoreLine("!build/")
+	assert.True(t, rule.negate)
+	assert.True(t, rule.dirOnly)
+}
+
+func TestAnalyzePathWithGitignore(t *testing.T) {
+	fin := createGitignoreTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetUseGitignore(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "main.go")
+	assert.Contains(t, output.String(), "keep.log")
+	assert.Contains(t, output.String(), "important.txt")
+	assert.NotContains(t, output.String(), "debug.log")
+	assert.NotContains(t, output.String(), "error.log")
+	assert.NotContains(t, output.String(), "/build")
+	assert.NotContains(t, output.String(), "vendor")
+	assert.NotContains(t, output.String(), "today.txt")
+}
+
+func TestAnalyzePathWithoutGitignore(t *testing.T) {
+	fin := createGitignoreTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "debug.log")
+	assert.Contains(t, output.String(), "vendor")
+}
@@ -0,0 +1,91 @@
+package stdout
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// localeSeparators are separators of thousands by language (or language and territory) of the locale,
+// other locales use comma
+var localeSeparators = map[string]string{
+	"de": ".", "nl": ".", "it": ".", "es": ".", "pt": ".", "da": ".", "id": ".", "tr": ".", "el": ".", "ro": ".",
+	"fr": " ", "ru": " ", "pl": " ", "cs": " ", "sk": " ", "sv": " ", "fi": " ", "nb": " ", "uk": " ", "hu": " ",
+	"de_CH": "'", "it_CH": "'",
+}
+
+// SetDigitGrouping sets separator of thousands in raw byte sizes and item counts of the text output
+// (e.g. "12,345,678"), "locale" means the separator of the locale set by LC_ALL, LC_NUMERIC or LANG
+// and empty separator disables grouping. Machine formats (e.g. JSON or CSV) are never grouped.
+func (ui *UI) SetDigitGrouping(separator string) error {
+	if separator == "locale" {
+		separator = getLocaleSeparator(os.Getenv)
+	}
+	if strings.ContainsAny(separator, "0123456789") {
+		return fmt.Errorf("invalid digit separator: %s", separator)
+	}
+	ui.digitSeparator = separator
+	return nil
+}
+
+// getLocaleSeparator returns separator of thousands of the current locale
+func getLocaleSeparator(getenv func(string) string) string {
+	var locale string
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if locale = getenv(name); locale != "" {
+			break
+		}
+	}
+	// strip encoding and modifier, e.g. de_DE.UTF-8@euro
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+
+	if sep, ok := localeSeparators[locale]; ok {
+		return sep
+	}
+	if i := strings.Index(locale, "_"); i >= 0 {
+		locale = locale[:i]
+	}
+	if sep, ok := localeSeparators[locale]; ok {
+		return sep
+	}
+	return ","
+}
+
+// formatCount returns the number with digits grouped by the separator set by SetDigitGrouping
+func (ui *UI) formatCount(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	if ui.digitSeparator == "" {
+		return s
+	}
+
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteString(ui.digitSeparator)
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
+
+// rawBytesWidth returns width of the size column with raw bytes, widened by separators of digit groups
+func (ui *UI) rawBytesWidth() int {
+	return rawBytesLength + (rawBytesLength-1)/3*len(ui.digitSeparator)
+}
+
+// pluralize returns the count followed by the singular or the plural form of the noun
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return strconv.Itoa(n) + " " + plural
+}
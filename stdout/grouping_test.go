@@ -0,0 +1,80 @@
+package stdout
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCount(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	assert.Equal(t, "12345678", ui.formatCount(12345678))
+
+	ui.SetDigitGrouping(",")
+	assert.Equal(t, "0", ui.formatCount(0))
+	assert.Equal(t, "999", ui.formatCount(999))
+	assert.Equal(t, "1,000", ui.formatCount(1000))
+	assert.Equal(t, "12,345", ui.formatCount(12345))
+	assert.Equal(t, "123,456", ui.formatCount(123456))
+	assert.Equal(t, "12,345,678", ui.formatCount(12345678))
+	assert.Equal(t, "-1,234", ui.formatCount(-1234))
+
+	ui.SetDigitGrouping(".")
+	assert.Equal(t, "1.099.511.627.777", ui.formatCount(1<<40+1))
+}
+
+func TestDigitGrouping(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetRawBytes(true)
+	ui.SetShowItemCount(true)
+	ui.SetDigitGrouping(" ")
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, "     1 099 511 627 777  5 /aaa", lines[0][1:])
+	assert.Equal(t, "                 1 025    ddd", lines[3][1:])
+	assert.Equal(t, "Total: 1 100 586 419 204, 12 items", lines[4])
+}
+
+func TestDigitGroupingInJSON(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetDigitGrouping(",")
+	ui.SetOutputFormat(JSONOutput)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "1099511627777")
+	assert.NotContains(t, output.String(), "1,099")
+}
+
+func TestSetInvalidDigitSeparator(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetDigitGrouping("0")
+
+	assert.Equal(t, "invalid digit separator: 0", err.Error())
+}
+
+func TestGetLocaleSeparator(t *testing.T) {
+	getenv := func(env map[string]string) func(string) string {
+		return func(name string) string { return env[name] }
+	}
+
+	assert.Equal(t, ",", getLocaleSeparator(getenv(map[string]string{})))
+	assert.Equal(t, ",", getLocaleSeparator(getenv(map[string]string{"LANG": "C"})))
+	assert.Equal(t, ",", getLocaleSeparator(getenv(map[string]string{"LANG": "en_US.UTF-8"})))
+	assert.Equal(t, ".", getLocaleSeparator(getenv(map[string]string{"LANG": "de_DE.UTF-8@euro"})))
+	assert.Equal(t, "'", getLocaleSeparator(getenv(map[string]string{"LANG": "de_CH.UTF-8"})))
+	assert.Equal(t, " ", getLocaleSeparator(getenv(map[string]string{"LANG": "en_US", "LC_NUMERIC": "fr_FR"})))
+	assert.Equal(t, ",", getLocaleSeparator(getenv(map[string]string{"LC_NUMERIC": "fr_FR", "LC_ALL": "en_GB"})))
+}
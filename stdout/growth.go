@@ -0,0 +1,120 @@
+package stdout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// scanTotal is the total size of the analyzed path remembered from the last scan
+type scanTotal struct {
+	Size  int64     `json:"size"`
+	Usage int64     `json:"usage"`
+	Time  time.Time `json:"time"`
+}
+
+// SetGrowthFile sets path of the state file totals of analyzed paths are remembered in (keyed by absolute path),
+// empty path disables it.
+// Growth of the total since the previous scan of the same path is printed after the results in text mode.
+// Totals are remembered only when the growth is printed, so other formats do not move the baseline.
+func (ui *UI) SetGrowthFile(path string) {
+	ui.growthFile = path
+}
+
+// printGrowth prints change of the total size of the dir since the last scan
+// and remembers the current total in the state file
+func (ui *UI) printGrowth(dir *analyze.Dir) error {
+	if ui.growthFile == "" || !ui.printsText() {
+		return nil
+	}
+
+	totals, err := loadScanTotals(ui.growthFile)
+	if err != nil {
+		return err
+	}
+
+	layout := ui.timeFormat
+	if layout == "" {
+		layout = defaultTimeFormat
+	}
+
+	path := dir.GetPath()
+	if last, ok := totals[path]; ok {
+		size := last.Usage
+		if ui.showApparentSize {
+			size = last.Size
+		}
+		fmt.Fprintf(
+			ui.output,
+			"%s since last scan (%s)\n",
+			ui.formatSizeDelta(ui.getSize(dir)-size),
+			last.Time.Local().Format(layout),
+		)
+	} else {
+		fmt.Fprintln(ui.output, "No previous scan to compare with")
+	}
+
+	totals[path] = scanTotal{
+		Size:  dir.GetSize(),
+		Usage: dir.GetUsage(),
+		Time:  ui.now(),
+	}
+	return saveScanTotals(ui.growthFile, totals)
+}
+
+// loadScanTotals reads totals remembered in the state file, missing file means no totals
+func loadScanTotals(path string) (map[string]scanTotal, error) {
+	totals := make(map[string]scanTotal)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return totals, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading growth file: %w", err)
+	}
+	if err := json.Unmarshal(data, &totals); err != nil {
+		return nil, fmt.Errorf("parsing growth file %s: %w", path, err)
+	}
+	return totals, nil
+}
+
+// saveScanTotals writes the totals to the state file, the file is replaced at once
+// so that it is never left half written (not even by concurrently running instances)
+func saveScanTotals(path string, totals map[string]scanTotal) error {
+	data, err := json.Marshal(totals)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("writing growth file: %w", err)
+	}
+	// the temporary file has to be on the same filesystem for the rename
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing growth file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("writing growth file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package stdout
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func runGrowthScan(t *testing.T, growthFile string, now time.Time, paths ...string) []string {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetGrowthFile(growthFile)
+	ui.now = func() time.Time { return now }
+
+	var err error
+	if len(paths) == 1 {
+		err = ui.AnalyzePath(paths[0], nil)
+	} else {
+		err = ui.AnalyzePaths(paths)
+	}
+	assert.Nil(t, err)
+
+	return strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+}
+
+func TestShowGrowth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	dir, err := ioutil.TempDir("", "gdu-growth")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	growthFile := filepath.Join(dir, "state", "growth.json")
+
+	first := time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local)
+	lines := runGrowthScan(t, growthFile, first, "test_dir")
+	assert.Equal(t, "No previous scan to compare with", lines[len(lines)-1])
+
+	os.WriteFile("test_dir/new_file", []byte("0123456789"), 0644)
+
+	lines = runGrowthScan(t, growthFile, first.Add(time.Hour), "test_dir")
+	assert.Equal(t, "+10 B since last scan (2026-10-13 10:00)", lines[len(lines)-1])
+
+	os.Remove("test_dir/nested/file2")
+
+	lines = runGrowthScan(t, growthFile, first.Add(2*time.Hour), "test_dir")
+	assert.Equal(t, "-2 B since last scan (2026-10-13 11:00)", lines[len(lines)-1])
+}
+
+func TestShowGrowthOfMorePaths(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	dir, err := ioutil.TempDir("", "gdu-growth")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	growthFile := filepath.Join(dir, "growth.json")
+
+	now := time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local)
+	runGrowthScan(t, growthFile, now, "test_dir/nested")
+
+	lines := runGrowthScan(t, growthFile, now, "test_dir/nested", "test_dir/nested/subnested")
+	assert.Contains(t, lines, "+0 B since last scan (2026-10-13 10:00)")
+	assert.Contains(t, lines, "No previous scan to compare with")
+
+	content, err := ioutil.ReadFile(growthFile)
+	assert.Nil(t, err)
+	abs, _ := filepath.Abs("test_dir/nested/subnested")
+	assert.Contains(t, string(content), `"`+abs+`":{"size":4101,`)
+}
+
+func TestShowGrowthWithInvalidFile(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	f, err := ioutil.TempFile("", "gdu-growth")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	f.WriteString("{")
+	f.Close()
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, true)
+	ui.SetGrowthFile(f.Name())
+	err = ui.AnalyzePath("test_dir", nil)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "parsing growth file")
+}
+
+func TestShowGrowthKeepsBaselineInOtherFormats(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	dir, err := ioutil.TempDir("", "gdu-growth")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	growthFile := filepath.Join(dir, "growth.json")
+
+	now := time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local)
+	runGrowthScan(t, growthFile, now, "test_dir")
+
+	os.WriteFile("test_dir/new_file", []byte("0123456789"), 0644)
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, true)
+	ui.SetGrowthFile(growthFile)
+	ui.SetOutputFormat(JSONOutput)
+	ui.now = func() time.Time { return now.Add(time.Hour) }
+	assert.Nil(t, ui.AnalyzePath("test_dir", nil))
+
+	lines := runGrowthScan(t, growthFile, now.Add(2*time.Hour), "test_dir")
+	assert.Equal(t, "+10 B since last scan (2026-10-13 10:00)", lines[len(lines)-1])
+
+	// no temporary files are left
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+}
@@ -0,0 +1,78 @@
+package stdout
+
+import (
+	"html/template"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+type htmlItem struct {
+	Name      string
+	Size      string
+	Percent   float64
+	IsDir     bool
+	ItemCount int
+	Open      bool
+	Children  []*htmlItem
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8" />
+<title>gdu: {{.Name}}</title>
+<style>
+body { font-family: sans-serif; }
+ul { list-style: none; padding-left: 1.5em; }
+summary { cursor: pointer; }
+.size { display: inline-block; width: 6em; text-align: right; font-family: monospace; }
+.bar { display: inline-block; width: 10em; height: 0.8em; border: 1px solid #999; }
+.bar span { display: block; height: 100%; background: #1ba1e3; }
+.percent { display: inline-block; width: 4em; text-align: right; font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<p>Total: {{.Size}}, {{.ItemCount}} items</p>
+<ul>
+{{template "item" .}}
+</ul>
+</body>
+</html>
+{{define "item"}}<li>{{if .Children}}<details{{if .Open}} open="open"{{end}}><summary>{{template "entry" .}}</summary>
+<ul>
+{{range .Children}}{{template "item" .}}
+{{end}}</ul>
+</details>{{else}}{{template "entry" .}}{{end}}</li>{{end}}
+{{define "entry"}}<span class="size">{{.Size}}</span> <span class="bar"><span style="width: {{printf "%.1f" .Percent}}%"></span></span> <span class="percent">{{printf "%.1f" .Percent}}%</span> {{.Name}}{{if .IsDir}}/{{end}}{{end}}`))
+
+// printHTML writes analyzed tree as HTML report with collapsible dirs
+func (ui *UI) printHTML(dir *analyze.Dir) error {
+	return htmlTemplate.Execute(ui.output, ui.createHTMLItem(dir, ui.getSize(dir), 0))
+}
+
+func (ui *UI) createHTMLItem(item analyze.Item, parentSize int64, depth int) *htmlItem {
+	size := ui.getSize(item)
+
+	res := &htmlItem{
+		Name:      item.GetName(),
+		Size:      ui.formatSize(size),
+		IsDir:     item.IsDir(),
+		ItemCount: item.GetItemCount(),
+		Open:      depth == 0,
+	}
+	if parentSize > 0 {
+		res.Percent = float64(size) / float64(parentSize) * 100
+	}
+
+	dir, ok := item.(*analyze.Dir)
+	if !ok || (depth > 0 && !ui.shouldExpand(depth)) {
+		return res
+	}
+
+	res.Children = make([]*htmlItem, 0, len(dir.Files))
+	for _, file := range ui.sortedFiles(ui.filterFiles(dir.Files)) {
+		res.Children = append(res.Children, ui.createHTMLItem(file, size, depth+1))
+	}
+	return res
+}
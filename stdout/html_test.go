@@ -0,0 +1,88 @@
+package stdout
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertWellFormed checks that all elements of the document are properly nested
+func assertWellFormed(t *testing.T, doc string) {
+	decoder := xml.NewDecoder(strings.NewReader(doc))
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	assert.Equal(t, 0, depth)
+}
+
+func TestAnalyzePathHTML(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, true, false, false)
+	ui.SetOutputFormat(HTMLOutput)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	doc := output.String()
+	assertWellFormed(t, doc)
+
+	assert.True(t, strings.HasPrefix(doc, "<!DOCTYPE html>"))
+	assert.NotContains(t, doc, "\x1b[")
+	assert.Contains(t, doc, "<title>gdu: test_dir</title>")
+	assert.Contains(t, doc, "<p>Total: 1.0 TiB, 12 items</p>")
+	assert.Contains(t, doc, `<details open="open"><summary>`)
+	assert.Contains(t, doc, `<span class="percent">100.0%</span> test_dir/`)
+	assert.Contains(t, doc, `<span class="size">1.0 TiB</span>`)
+	assert.Contains(t, doc, `<span class="percent">0.0%</span> ddd</li>`)
+	assert.Equal(t, 5, strings.Count(doc, "<li>"))
+}
+
+func TestAnalyzePathHTMLRecursive(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/<script>", []byte("abc"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(HTMLOutput)
+	ui.SetRecursive(true)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	doc := output.String()
+	assertWellFormed(t, doc)
+
+	assert.Contains(t, doc, ", 6 items</p>")
+	assert.Contains(t, doc, "</span> nested/</summary>")
+	assert.Contains(t, doc, "</span> subnested/</summary>")
+	assert.Contains(t, doc, `<span class="size">5 B</span>`)
+	assert.Contains(t, doc, `<span class="size">2 B</span>`)
+	assert.Equal(t, 3, strings.Count(doc, "<details"))
+	assert.Contains(t, doc, "&lt;script&gt;</li>")
+	assert.NotContains(t, doc, "<script>")
+}
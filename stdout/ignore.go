@@ -0,0 +1,137 @@
+package stdout
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SetIgnoreDirPaths sets paths to ignore
+func (ui *UI) SetIgnoreDirPaths(paths []string) {
+	ui.ignoreDirPaths = make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		ui.ignoreDirPaths[path] = struct{}{}
+	}
+}
+
+// SetIgnoreDirPathsFromFile adds paths to ignore read from given file, one per line.
+// Lines starting with "re:" are regular expressions, lines containing any of "*?[" are glob patterns
+// and other lines are exact paths. Blank lines and lines starting with "#" are skipped.
+func (ui *UI) SetIgnoreDirPathsFromFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("reading exclude file: %w", err)
+	}
+	defer f.Close()
+
+	if ui.ignoreDirPaths == nil {
+		ui.ignoreDirPaths = make(map[string]struct{})
+	}
+
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "re:"):
+			re, err := regexp.Compile(strings.TrimPrefix(line, "re:"))
+			if err != nil {
+				return fmt.Errorf("invalid ignore regex on line %d of %s: %w", lineNumber, path, err)
+			}
+			ui.ignoreRegexps = append(ui.ignoreRegexps, re)
+		case strings.ContainsAny(line, "*?["):
+			if _, err := filepath.Match(line, ""); err != nil {
+				return fmt.Errorf("invalid ignore pattern on line %d of %s: %w", lineNumber, path, err)
+			}
+			ui.ignorePatterns = append(ui.ignorePatterns, line)
+		default:
+			ui.ignoreDirPaths[filepath.Clean(line)] = struct{}{}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading exclude file: %w", err)
+	}
+	return nil
+}
+
+// SetIgnoreDirPatterns sets glob patterns of paths to ignore.
+// Absolute patterns are matched against the whole path,
+// relative ones against the same number of trailing path components
+// (e.g. "*/node_modules" matches node_modules dir anywhere in the tree).
+func (ui *UI) SetIgnoreDirPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid ignore pattern %s: %w", pattern, err)
+		}
+	}
+	ui.ignorePatterns = patterns
+	return nil
+}
+
+// SetIgnoreDirRegex sets regular expressions matching paths to ignore
+func (ui *UI) SetIgnoreDirRegex(patterns []string) error {
+	regexps := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid ignore regex %s: %w", pattern, err)
+		}
+		regexps = append(regexps, re)
+	}
+	ui.ignoreRegexps = regexps
+	return nil
+}
+
+// SetUseGitignore sets whether paths matched by .gitignore files
+// found in the analyzed tree should be ignored (dirs by ShouldDirBeIgnored and files by ShouldFileBeIgnored)
+func (ui *UI) SetUseGitignore(useGitignore bool) {
+	ui.useGitignore = useGitignore
+}
+
+// ShouldDirBeIgnored returns true if given path should be ignored
+func (ui *UI) ShouldDirBeIgnored(path string) bool {
+	if _, ok := ui.ignoreDirPaths[path]; ok {
+		return true
+	}
+
+	for _, pattern := range ui.ignorePatterns {
+		if matchPattern(pattern, path) {
+			return true
+		}
+	}
+
+	for _, re := range ui.ignoreRegexps {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+
+	if ui.gitignore != nil && ui.gitignore.isIgnored(path, true) {
+		return true
+	}
+	return false
+}
+
+// ShouldFileBeIgnored returns true if given path of file is ignored by .gitignore files
+func (ui *UI) ShouldFileBeIgnored(path string) bool {
+	return ui.gitignore != nil && ui.gitignore.isIgnored(path, false)
+}
+
+func matchPattern(pattern, path string) bool {
+	if !filepath.IsAbs(pattern) {
+		parts := strings.Split(path, string(filepath.Separator))
+		count := strings.Count(pattern, string(filepath.Separator)) + 1
+		if count < len(parts) {
+			path = filepath.Join(parts[len(parts)-count:]...)
+		}
+	}
+
+	matched, _ := filepath.Match(pattern, path)
+	return matched
+}
@@ -0,0 +1,147 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnoreDirPaths(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetIgnoreDirPaths([]string{"/xxx", "/yyy/zzz"})
+
+	assert.True(t, ui.ShouldDirBeIgnored("/xxx"))
+	assert.True(t, ui.ShouldDirBeIgnored("/yyy/zzz"))
+	assert.False(t, ui.ShouldDirBeIgnored("/yyy"))
+	assert.False(t, ui.ShouldDirBeIgnored("/xxx/yyy"))
+}
+
+func TestIgnoreDirPatterns(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetIgnoreDirPatterns([]string{"/home/*/.cache", "*/node_modules", "*.tmp"})
+	assert.Nil(t, err)
+
+	assert.True(t, ui.ShouldDirBeIgnored("/home/user/.cache"))
+	assert.True(t, ui.ShouldDirBeIgnored("/home/user/project/node_modules"))
+	assert.True(t, ui.ShouldDirBeIgnored("/srv/node_modules"))
+	assert.True(t, ui.ShouldDirBeIgnored("/var/cache/xxx.tmp"))
+
+	assert.False(t, ui.ShouldDirBeIgnored("/home/user/project/.cache"))
+	assert.False(t, ui.ShouldDirBeIgnored("/home/user/.cache2"))
+	assert.False(t, ui.ShouldDirBeIgnored("/home/user/node_modules2"))
+	assert.False(t, ui.ShouldDirBeIgnored("/var/tmp"))
+}
+
+func TestIgnoreDirPatternsWithExactPaths(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetIgnoreDirPaths([]string{"/proc"})
+	ui.SetIgnoreDirPatterns([]string{"/home/*"})
+
+	assert.True(t, ui.ShouldDirBeIgnored("/proc"))
+	assert.True(t, ui.ShouldDirBeIgnored("/home/user"))
+	assert.False(t, ui.ShouldDirBeIgnored("/var"))
+}
+
+func TestIgnoreDirPatternsWithErr(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetIgnoreDirPatterns([]string{"*/ok", "[abc"})
+
+	assert.Equal(t, "invalid ignore pattern [abc: syntax error in pattern", err.Error())
+	assert.False(t, ui.ShouldDirBeIgnored("/xxx/ok"))
+}
+
+func TestIgnoreDirRegex(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetIgnoreDirRegex([]string{`.*/\.git$`, `cache`})
+	assert.Nil(t, err)
+
+	// anchored pattern
+	assert.True(t, ui.ShouldDirBeIgnored("/home/user/project/.git"))
+	assert.False(t, ui.ShouldDirBeIgnored("/home/user/project/.github"))
+	assert.False(t, ui.ShouldDirBeIgnored("/home/user/project/git"))
+
+	// unanchored pattern matches anywhere in the path
+	assert.True(t, ui.ShouldDirBeIgnored("/var/cache"))
+	assert.True(t, ui.ShouldDirBeIgnored("/home/user/.cache/xxx"))
+	assert.False(t, ui.ShouldDirBeIgnored("/var/lib"))
+}
+
+func TestIgnoreDirRegexAnchoredAtStart(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetIgnoreDirRegex([]string{`^/tmp`})
+	assert.Nil(t, err)
+
+	assert.True(t, ui.ShouldDirBeIgnored("/tmp/xxx"))
+	assert.False(t, ui.ShouldDirBeIgnored("/var/tmp"))
+}
+
+func TestIgnoreDirRegexWithErr(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetIgnoreDirRegex([]string{`ok`, `(unclosed`})
+
+	assert.Equal(t, "invalid ignore regex (unclosed: error parsing regexp: missing closing ): `(unclosed`", err.Error())
+	assert.False(t, ui.ShouldDirBeIgnored("/ok"))
+}
+
+func TestAnalyzePathWithIgnoreDirPatterns(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetRecursive(true)
+	ui.SetIgnoreDirPatterns([]string{"nested/sub*"})
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "file2")
+	assert.NotContains(t, output.String(), "subnested")
+}
+
+func TestIgnoreDirPathsFromFile(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	content := `# shared excludes
+/proc
+
+  /var/cache/  
+*/node_modules
+re:^/home/[^/]+/\.cache$
+`
+	os.WriteFile("test_dir/excludes", []byte(content), 0644)
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetIgnoreDirPaths([]string{"/sys"})
+	err := ui.SetIgnoreDirPathsFromFile("test_dir/excludes")
+	assert.Nil(t, err)
+
+	assert.True(t, ui.ShouldDirBeIgnored("/sys"))
+	assert.True(t, ui.ShouldDirBeIgnored("/proc"))
+	assert.True(t, ui.ShouldDirBeIgnored("/var/cache"))
+	assert.True(t, ui.ShouldDirBeIgnored("/srv/app/node_modules"))
+	assert.True(t, ui.ShouldDirBeIgnored("/home/user/.cache"))
+	assert.False(t, ui.ShouldDirBeIgnored("/home/user/.cache/xxx"))
+	assert.False(t, ui.ShouldDirBeIgnored("/var"))
+	assert.False(t, ui.ShouldDirBeIgnored("# shared excludes"))
+}
+
+func TestIgnoreDirPathsFromMissingFile(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetIgnoreDirPathsFromFile("missing")
+	assert.Equal(t, "reading exclude file: open missing: no such file or directory", err.Error())
+}
+
+func TestIgnoreDirPathsFromFileWithInvalidRegex(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/excludes", []byte("/proc\nre:[\n"), 0644)
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetIgnoreDirPathsFromFile("test_dir/excludes")
+	assert.Contains(t, err.Error(), "invalid ignore regex on line 2 of test_dir/excludes")
+}
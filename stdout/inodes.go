@@ -0,0 +1,39 @@
+package stdout
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/dundee/gdu/v4/device"
+)
+
+// inodesColumnLength returns width of columns with inode counts
+func inodesColumnLength(devices device.Devices) int {
+	length := len("Inodes")
+	for _, dev := range devices {
+		length = maxInt(length, len(strconv.FormatInt(dev.Inodes, 10)))
+	}
+	return length
+}
+
+// formatInodesUsedPercent returns percentage of used inodes ("-" if the filesystem does not report inodes)
+func (ui *UI) formatInodesUsedPercent(dev *device.Device) string {
+	// some filesystems (e.g. btrfs) do not report number of inodes
+	usedPercent := "-"
+	if dev.Inodes > 0 {
+		usedPercent = fmt.Sprintf(
+			"%.f%%",
+			math.Round(float64(dev.Inodes-dev.InodesFree)/float64(dev.Inodes)*100),
+		)
+	}
+	return ui.formatPercentWithColor(fmt.Sprintf("%5s", usedPercent))
+}
+
+// formatPercentWithColor highlights the percentage by red color unless coloring of percentages is disabled
+func (ui *UI) formatPercentWithColor(percent string) string {
+	if !ui.colorPercent {
+		return percent
+	}
+	return ui.red.Sprint(percent)
+}
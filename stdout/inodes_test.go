@@ -0,0 +1,69 @@
+package stdout
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/device"
+	"github.com/dundee/gdu/v4/internal/testdev"
+	"github.com/stretchr/testify/assert"
+)
+
+func getDevicesInfoWithInodesMock() testdev.DevicesInfoGetterMock {
+	return testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{
+				Name:       "/dev/sda1",
+				MountPoint: "/",
+				Size:       1 << 30,
+				Free:       1 << 29,
+				Inodes:     1000000,
+				InodesFree: 250000,
+			},
+			{
+				Name:       "/dev/sdb1",
+				MountPoint: "/mnt/btrfs",
+				Size:       1 << 30,
+				Free:       1 << 30,
+			},
+		},
+	}
+}
+
+func TestShowDevicesWithInodes(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowInodes(true)
+	err := ui.ListDevices(getDevicesInfoWithInodesMock())
+	assert.Nil(t, err)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   Device      Size      Used      Free Used%  Inodes   IUsed   IFree IUse% Mount point", lines[0])
+	assert.Equal(t, "/dev/sda1   1.0 GiB 512.0 MiB 512.0 MiB   50% 1000000  750000  250000   75% /", lines[1])
+	assert.Equal(t, "/dev/sdb1   1.0 GiB       0 B   1.0 GiB    0%       0       0       0     - /mnt/btrfs", lines[2])
+}
+
+func TestShowDevicesWithoutInodes(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	err := ui.ListDevices(getDevicesInfoWithInodesMock())
+	assert.Nil(t, err)
+
+	assert.NotContains(t, output.String(), "Inodes")
+	assert.NotContains(t, output.String(), "750000")
+}
+
+func TestShowDevicesWithInodesAndColors(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, true, false, false)
+	ui.SetShowInodes(true)
+	err := ui.ListDevices(getDevicesInfoWithInodesMock())
+	assert.Nil(t, err)
+
+	assert.Contains(t, output.String(), "IUse%")
+	assert.Contains(t, output.String(), "75%")
+}
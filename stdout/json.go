@@ -0,0 +1,86 @@
+package stdout
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/device"
+)
+
+type jsonItem struct {
+	Name      string      `json:"name"`
+	Size      int64       `json:"size"`
+	Usage     int64       `json:"usage"`
+	IsDir     bool        `json:"isDir"`
+	ItemCount int         `json:"itemCount"`
+	Children  []*jsonItem `json:"children,omitempty"`
+}
+
+func (ui *UI) printJSON(dir *analyze.Dir) error {
+	encoder := json.NewEncoder(ui.output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(ui.createJSONItem(dir, 0))
+}
+
+// printJSONDirs prints the dirs as one JSON array, the dirs are named by their absolute paths
+func (ui *UI) printJSONDirs(dirs []*analyze.Dir) error {
+	items := make([]*jsonItem, 0, len(dirs))
+	for _, dir := range dirs {
+		item := ui.createJSONItem(dir, 0)
+		item.Name = dir.GetPath()
+		items = append(items, item)
+	}
+
+	encoder := json.NewEncoder(ui.output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(items)
+}
+
+func (ui *UI) createJSONItem(item analyze.Item, depth int) *jsonItem {
+	res := &jsonItem{
+		Name:      item.GetName(),
+		Size:      item.GetSize(),
+		Usage:     item.GetUsage(),
+		IsDir:     item.IsDir(),
+		ItemCount: item.GetItemCount(),
+	}
+
+	dir, ok := item.(*analyze.Dir)
+	if !ok || (depth > 0 && !ui.shouldExpand(depth)) {
+		return res
+	}
+
+	res.Children = make([]*jsonItem, 0, len(dir.Files))
+	for _, file := range ui.sortedFiles(ui.filterFiles(dir.Files)) {
+		res.Children = append(res.Children, ui.createJSONItem(file, depth+1))
+	}
+	return res
+}
+
+type jsonDevice struct {
+	Name        string  `json:"name"`
+	MountPoint  string  `json:"mountPoint"`
+	Size        int64   `json:"size"`
+	Used        int64   `json:"used"`
+	Free        int64   `json:"free"`
+	UsedPercent float64 `json:"usedPercent"`
+}
+
+func (ui *UI) printDevicesJSON(devices device.Devices) error {
+	res := make([]*jsonDevice, 0, len(devices))
+	for _, dev := range devices {
+		res = append(res, &jsonDevice{
+			Name:        dev.Name,
+			MountPoint:  dev.MountPoint,
+			Size:        dev.Size,
+			Used:        dev.Size - dev.Free,
+			Free:        dev.Free,
+			UsedPercent: math.Round(getUsedPercent(dev)*100) / 100,
+		})
+	}
+
+	encoder := json.NewEncoder(ui.output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(res)
+}
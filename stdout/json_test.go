@@ -0,0 +1,164 @@
+package stdout
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/dundee/gdu/v4/device"
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdev"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzePathJSON(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, true, false)
+	ui.SetOutputFormat(JSONOutput)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	var root jsonItem
+	err = json.Unmarshal(output.Bytes(), &root)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "test_dir", root.Name)
+	assert.True(t, root.IsDir)
+	assert.Equal(t, 12, root.ItemCount)
+	assert.Len(t, root.Children, 4)
+
+	assert.Equal(t, "aaa", root.Children[0].Name)
+	assert.Equal(t, int64(1<<40+2), root.Children[0].Size)
+	assert.Equal(t, int64(1<<40+1), root.Children[0].Usage)
+	assert.Equal(t, 5, root.Children[0].ItemCount)
+	assert.True(t, root.Children[0].IsDir)
+	assert.Equal(t, "ddd", root.Children[3].Name)
+	assert.False(t, root.Children[3].IsDir)
+	assert.Equal(t, 1, root.Children[3].ItemCount)
+}
+
+func TestAnalyzePathJSONTopLevel(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(JSONOutput)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	var root jsonItem
+	err = json.Unmarshal(output.Bytes(), &root)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "test_dir", root.Name)
+	assert.Len(t, root.Children, 1)
+	assert.Equal(t, "nested", root.Children[0].Name)
+	assert.Equal(t, 4, root.Children[0].ItemCount)
+	assert.Empty(t, root.Children[0].Children)
+}
+
+func TestAnalyzePathJSONRecursive(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(JSONOutput)
+	ui.SetRecursive(true)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	var root jsonItem
+	err = json.Unmarshal(output.Bytes(), &root)
+	assert.Nil(t, err)
+
+	nested := root.Children[0]
+	assert.Equal(t, "nested", nested.Name)
+	assert.Len(t, nested.Children, 2)
+
+	// sorted by apparent size
+	assert.Equal(t, "subnested", nested.Children[0].Name)
+	assert.Equal(t, "file2", nested.Children[1].Name)
+	assert.Equal(t, int64(2), nested.Children[1].Size)
+
+	assert.Len(t, nested.Children[0].Children, 1)
+	assert.Equal(t, "file", nested.Children[0].Children[0].Name)
+	assert.Equal(t, int64(5), nested.Children[0].Children[0].Size)
+}
+
+func TestAnalyzePathJSONWithMaxDepth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(JSONOutput)
+	ui.SetRecursive(true)
+	ui.SetMaxDepth(2)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	var root jsonItem
+	err = json.Unmarshal(output.Bytes(), &root)
+	assert.Nil(t, err)
+
+	nested := root.Children[0]
+	assert.Len(t, nested.Children, 2)
+	assert.Equal(t, "subnested", nested.Children[0].Name)
+	assert.Empty(t, nested.Children[0].Children)
+}
+
+func TestJSONIsDeterministic(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	outputs := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		output := bytes.NewBuffer(nil)
+		ui := CreateStdoutUI(output, false, false, true)
+		ui.SetOutputFormat(JSONOutput)
+		ui.SetRecursive(true)
+		ui.AnalyzePath("test_dir", nil)
+		outputs = append(outputs, output.String())
+	}
+
+	assert.Equal(t, outputs[0], outputs[1])
+}
+
+func TestShowDevicesJSON(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, true, false, false)
+	ui.SetOutputFormat(JSONOutput)
+	err := ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{
+			{Name: "/dev/sda1", MountPoint: "/", Size: 1 << 30, Free: 1 << 28, Bfree: 1 << 29},
+			{Name: "/dev/sdb1", MountPoint: "/mnt/data", Size: 3000, Free: 2000},
+		},
+	})
+	assert.Nil(t, err)
+
+	var devices []map[string]interface{}
+	err = json.Unmarshal(output.Bytes(), &devices)
+	assert.Nil(t, err)
+	assert.Len(t, devices, 2)
+
+	assert.Equal(t, map[string]interface{}{
+		"name":        "/dev/sda1",
+		"mountPoint":  "/",
+		"size":        float64(1 << 30),
+		"used":        float64(3 << 28),
+		"free":        float64(1 << 28),
+		"usedPercent": float64(75),
+	}, devices[0])
+	assert.Equal(t, float64(1000), devices[1]["used"])
+	assert.Equal(t, 33.33, devices[1]["usedPercent"])
+}
@@ -0,0 +1,133 @@
+package stdout
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetLargestFiles sets that only given number of the largest files found anywhere in the analyzed tree
+// are printed with full paths instead of the listing of the dir (0 disables this mode)
+func (ui *UI) SetLargestFiles(n int) {
+	ui.largestFiles = n
+}
+
+// SetLargestDirs sets that only given number of the largest dirs found anywhere in the analyzed tree
+// are printed with full paths instead of the listing of the dir (0 disables this mode).
+// Nested dirs are ranked independently, so a dir and its subdir can both be printed.
+func (ui *UI) SetLargestDirs(n int) {
+	ui.largestDirs = n
+}
+
+// sizeHeap is min-heap of items ordered by size, the smallest item is on top
+type sizeHeap struct {
+	items   analyze.Files
+	getSize func(analyze.Item) int64
+}
+
+func (h *sizeHeap) Len() int { return len(h.items) }
+func (h *sizeHeap) Less(i, j int) bool {
+	si, sj := h.getSize(h.items[i]), h.getSize(h.items[j])
+	if si != sj {
+		return si < sj
+	}
+	return h.items[i].GetPath() > h.items[j].GetPath()
+}
+func (h *sizeHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *sizeHeap) Push(x interface{}) { h.items = append(h.items, x.(analyze.Item)) }
+func (h *sizeHeap) Pop() interface{} {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// push adds the item to the heap and drops the smallest item if the heap exceeds n items
+func (h *sizeHeap) push(item analyze.Item, n int) {
+	heap.Push(h, item)
+	if h.Len() > n {
+		heap.Pop(h)
+	}
+}
+
+// findLargestFiles returns at most n largest files in the dir tree sorted from the largest
+func (ui *UI) findLargestFiles(dir *analyze.Dir, n int) analyze.Files {
+	h := &sizeHeap{items: make(analyze.Files, 0, n+1), getSize: ui.getSize}
+	ui.collectLargestFiles(dir, h, n)
+
+	sort.Sort(sort.Reverse(h))
+	return h.items
+}
+
+func (ui *UI) collectLargestFiles(dir *analyze.Dir, h *sizeHeap, n int) {
+	for _, item := range dir.Files {
+		if subdir, ok := item.(*analyze.Dir); ok {
+			if ui.showHidden || !isHidden(subdir.GetName()) {
+				ui.collectLargestFiles(subdir, h, n)
+			}
+		} else if ui.shouldBePrinted(item) {
+			h.push(item, n)
+		}
+	}
+}
+
+// findLargestDirs returns at most n largest dirs in the dir tree (without the dir itself)
+// sorted from the largest
+func (ui *UI) findLargestDirs(dir *analyze.Dir, n int) analyze.Files {
+	h := &sizeHeap{items: make(analyze.Files, 0, n+1), getSize: ui.getSize}
+	ui.collectLargestDirs(dir, h, n)
+
+	sort.Sort(sort.Reverse(h))
+	return h.items
+}
+
+func (ui *UI) collectLargestDirs(dir *analyze.Dir, h *sizeHeap, n int) {
+	for _, item := range dir.Files {
+		subdir, ok := item.(*analyze.Dir)
+		if !ok || !ui.showHidden && isHidden(subdir.GetName()) {
+			continue
+		}
+		if ui.shouldBePrinted(subdir) {
+			h.push(subdir, n)
+		}
+		ui.collectLargestDirs(subdir, h, n)
+	}
+}
+
+// printLargest prints the largest files or dirs of the whole tree
+func (ui *UI) printLargest(dir *analyze.Dir) {
+	ui.setSizeColumnWidth(ui.getSize(dir))
+	ui.rootPath = dir.GetPath()
+
+	var prefixFormat string
+	switch {
+	case ui.rawBytes:
+		prefixFormat = fmt.Sprintf("%%s %%%ds ", ui.rawBytesWidth())
+	case ui.useColors:
+		// size is padded in formatItemSize as it can contain color codes of different lengths
+		prefixFormat = "%s %s "
+	default:
+		prefixFormat = fmt.Sprintf("%%s %%%ds ", ui.sizeWidth())
+	}
+
+	var items analyze.Files
+	if ui.largestDirs > 0 {
+		items = ui.findLargestDirs(dir, ui.largestDirs)
+	} else {
+		items = ui.findLargestFiles(dir, ui.largestFiles)
+	}
+
+	for _, file := range items {
+		path := file.GetPath()
+		if ui.showRelativePath {
+			path = ui.relativePath(file)
+		}
+		prefix := fmt.Sprintf(prefixFormat, string(file.GetFlag()), ui.formatItemSize(ui.getSize(file)))
+		fmt.Fprintf(ui.output, "%s%s\n", prefix, ui.truncateName(path, ui.getNameWidth(prefix)))
+	}
+
+	if ui.showTotal {
+		ui.printTotal(dir)
+	}
+}
@@ -0,0 +1,168 @@
+package stdout
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func createLargestFilesTestDir() func() {
+	fin := testdir.CreateTestDir()
+	os.MkdirAll("test_dir/nested/subnested/deep", os.ModePerm)
+	os.WriteFile("test_dir/nested/subnested/deep/big", bytes.Repeat([]byte("x"), 300), 0644)
+	os.WriteFile("test_dir/nested/medium", bytes.Repeat([]byte("x"), 200), 0644)
+	os.WriteFile("test_dir/small", bytes.Repeat([]byte("x"), 100), 0644)
+	return fin
+}
+
+func TestLargestFiles(t *testing.T) {
+	fin := createLargestFilesTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetLargestFiles(3)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	root, _ := filepath.Abs("test_dir")
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "     300 B "+root+"/nested/subnested/deep/big", lines[0][1:])
+	assert.Equal(t, "     200 B "+root+"/nested/medium", lines[1][1:])
+	assert.Equal(t, "     100 B "+root+"/small", lines[2][1:])
+	assert.True(t, strings.HasPrefix(lines[3], "Total: "))
+	assert.Equal(t, "", lines[4])
+}
+
+func TestLargestFilesMoreThanFound(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetLargestFiles(10)
+	ui.SetShowTotal(false)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	// dirs are never listed
+	root, _ := filepath.Abs("test_dir")
+	assert.Equal(t,
+		"        5 B "+root+"/nested/subnested/file\n"+
+			"        2 B "+root+"/nested/file2\n",
+		output.String(),
+	)
+}
+
+func TestLargestFilesWithoutHidden(t *testing.T) {
+	fin := createLargestFilesTestDir()
+	defer fin()
+	os.MkdirAll("test_dir/.cache", os.ModePerm)
+	os.WriteFile("test_dir/.cache/huge", bytes.Repeat([]byte("x"), 400), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetLargestFiles(1)
+	ui.SetShowHidden(false)
+	ui.SetShowTotal(false)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	// files in hidden dirs are left out as well
+	root, _ := filepath.Abs("test_dir")
+	assert.Equal(t, "      300 B "+root+"/nested/subnested/deep/big\n", output.String())
+}
+
+func TestFindLargestFiles(t *testing.T) {
+	fin := createLargestFilesTestDir()
+	defer fin()
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, true)
+	dir, err := ui.analyzePath(context.Background(), "test_dir")
+	assert.Nil(t, err)
+
+	for n := 1; n <= 5; n++ {
+		files := ui.findLargestFiles(dir, n)
+		assert.Len(t, files, n)
+		for i := 1; i < len(files); i++ {
+			assert.GreaterOrEqual(t, files[i-1].GetSize(), files[i].GetSize())
+		}
+	}
+	assert.Equal(t, "big", ui.findLargestFiles(dir, 1)[0].GetName())
+}
+
+func TestLargestDirs(t *testing.T) {
+	fin := createLargestFilesTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetLargestDirs(3)
+	ui.SetShowTotal(false)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	// nested dirs are ranked independently of their parents
+	root, _ := filepath.Abs("test_dir")
+	lines := strings.Split(output.String(), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "  12.5 KiB "+root+"/nested", lines[0][1:])
+	assert.Equal(t, "   8.3 KiB "+root+"/nested/subnested", lines[1][1:])
+	assert.Equal(t, "   4.3 KiB "+root+"/nested/subnested/deep", lines[2][1:])
+}
+
+func TestLargestDirsWithoutHidden(t *testing.T) {
+	fin := createLargestFilesTestDir()
+	defer fin()
+	os.MkdirAll("test_dir/.cache/objects", os.ModePerm)
+	os.WriteFile("test_dir/.cache/objects/huge", bytes.Repeat([]byte("x"), 40000), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetLargestDirs(1)
+	ui.SetShowHidden(false)
+	ui.SetShowTotal(false)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	// dirs in hidden dirs are left out as well
+	root, _ := filepath.Abs("test_dir")
+	assert.Equal(t, "   12.5 KiB "+root+"/nested\n", output.String())
+}
+
+func TestFindLargestDirsInDeepTree(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	deep := "test_dir"
+	for i := 0; i < 10; i++ {
+		deep = filepath.Join(deep, "d")
+	}
+	os.MkdirAll(deep, os.ModePerm)
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, true)
+	dir, err := ui.analyzePath(context.Background(), "test_dir")
+	assert.Nil(t, err)
+
+	dirs := ui.findLargestDirs(dir, 20)
+	assert.Len(t, dirs, 12)
+	for i := 1; i < len(dirs); i++ {
+		assert.GreaterOrEqual(t, dirs[i-1].GetSize(), dirs[i].GetSize())
+	}
+	for _, d := range dirs {
+		assert.True(t, d.IsDir())
+		assert.NotEqual(t, dir, d)
+	}
+	assert.Equal(t, filepath.Join(dir.GetPath(), deep[len("test_dir"):]), dirs[len(dirs)-1].GetPath())
+}
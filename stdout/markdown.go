@@ -0,0 +1,43 @@
+package stdout
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// printMarkdown writes top-level items of the dirs as GitHub-flavored Markdown table
+// with one total row of all the dirs
+func (ui *UI) printMarkdown(dirs ...*analyze.Dir) error {
+	var (
+		totalSize  int64
+		totalCount int
+	)
+
+	fmt.Fprintln(ui.output, "| Path | Size | Items |")
+	fmt.Fprintln(ui.output, "|:---|---:|---:|")
+
+	for _, dir := range dirs {
+		files, _ := ui.selectFiles(dir.Files)
+
+		for _, file := range files {
+			fmt.Fprintf(
+				ui.output,
+				"| %s | %s | %d |\n",
+				markdownEscaper.Replace(file.GetPath()),
+				ui.formatSize(ui.getSize(file)),
+				file.GetItemCount(),
+			)
+		}
+
+		size, count := ui.getTotal(dir)
+		totalSize += size
+		totalCount += count
+	}
+
+	fmt.Fprintf(ui.output, "| **Total** | %s | %d |\n", ui.formatSize(totalSize), totalCount)
+	return nil
+}
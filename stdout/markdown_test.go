@@ -0,0 +1,68 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzePathMarkdown(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, true, false, false)
+	ui.SetOutputFormat(MarkdownOutput)
+	ui.SetMaxEntries(2)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	assert.True(t, color.NoColor)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Equal(t, []string{
+		"| Path | Size | Items |",
+		"|:---|---:|---:|",
+		"| test_dir/aaa | 1.0 TiB | 5 |",
+		"| test_dir/bbb | 1.0 GiB | 3 |",
+		"| **Total** | 1.0 TiB | 12 |",
+	}, lines)
+}
+
+func TestAnalyzePathMarkdownSortedByName(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetOutputFormat(MarkdownOutput)
+	ui.SetSorting("name", "asc")
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Equal(t, "| test_dir/aaa | 1.0 TiB | 5 |", lines[2])
+	assert.Equal(t, "| test_dir/ddd | 1.0 KiB | 1 |", lines[5])
+}
+
+func TestAnalyzePathMarkdownEscaping(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/with|pipe", []byte("abc"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(MarkdownOutput)
+	ui.AnalyzePath("test_dir", nil)
+
+	abspath, _ := filepath.Abs("test_dir")
+	assert.Contains(t, output.String(), "| "+abspath+`/with\|pipe | 3 B | 1 |`)
+}
@@ -0,0 +1,61 @@
+package stdout
+
+import (
+	"fmt"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/mattn/go-runewidth"
+)
+
+// SetMergedRanking sets that top-level items of all paths analyzed by AnalyzePaths are printed
+// in a single ranking (sorted, filtered and limited as the listing of one dir), each with the path it comes from,
+// instead of a separate listing of each path.
+// Reports of the paths (e.g. warnings about unreadable dirs) are printed after the ranking.
+func (ui *UI) SetMergedRanking(merged bool) {
+	ui.mergedRanking = merged
+}
+
+// printMergedRanking prints top-level items of all the dirs together, tagged with path of their dir
+func (ui *UI) printMergedRanking(dirs []*analyze.Dir) {
+	var (
+		items     analyze.Files
+		maxSize   int64
+		rootWidth int
+	)
+	for _, dir := range dirs {
+		items = append(items, dir.Files...)
+		if ui.getSize(dir) > maxSize {
+			maxSize = ui.getSize(dir)
+		}
+		if width := runewidth.StringWidth(dir.GetPath()); width > rootWidth {
+			rootWidth = width
+		}
+	}
+	ui.setSizeColumnWidth(maxSize)
+
+	// paths are padded by runewidth as fmt pads by the number of runes
+	var prefixFormat string
+	switch {
+	case ui.rawBytes:
+		prefixFormat = fmt.Sprintf("%%s %%%ds %%s ", ui.rawBytesWidth())
+	case ui.useColors:
+		// size is padded in formatItemSize as it can contain color codes of different lengths
+		prefixFormat = "%s %s %s "
+	default:
+		prefixFormat = fmt.Sprintf("%%s %%%ds %%s ", ui.sizeWidth())
+	}
+
+	files, hidden := ui.selectFiles(items)
+	for _, file := range files {
+		ui.rootPath = file.GetParent().GetPath()
+		prefix := fmt.Sprintf(prefixFormat,
+			string(file.GetFlag()),
+			ui.formatItemSize(ui.getSize(file)),
+			runewidth.FillRight(ui.rootPath, rootWidth))
+		fmt.Fprintf(ui.output, "%s%s\n", prefix, ui.formatItemName(file, ui.getNameWidth(prefix)))
+	}
+
+	if hidden > 0 {
+		fmt.Fprintf(ui.output, "... and %d more items\n", hidden)
+	}
+}
@@ -0,0 +1,119 @@
+package stdout
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergedRanking(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/other", os.ModePerm)
+	os.WriteFile("test_dir/other/big", []byte("xxxxxxxxxx"), 0644)
+	os.WriteFile("test_dir/other/small", []byte("x"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetMergedRanking(true)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/other"})
+	assert.Nil(t, err)
+
+	nested, _ := filepath.Abs("test_dir/nested")
+	other, _ := filepath.Abs("test_dir/other")
+	format := fmt.Sprintf("  %%9s %%-%ds %%s", len(nested))
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		fmt.Sprintf(format, "4.0 KiB", nested, "/subnested"),
+		fmt.Sprintf(format, "10 B", other, "big"),
+		fmt.Sprintf(format, "2 B", nested, "file2"),
+		fmt.Sprintf(format, "1 B", other, "small"),
+		"",
+		"Grand total: 12.0 KiB, 7 items",
+	}, lines)
+}
+
+func TestMergedRankingWithTop(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/other", os.ModePerm)
+	os.WriteFile("test_dir/other/big", []byte("xxxxxxxxxx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetMergedRanking(true)
+	ui.SetMaxEntries(2)
+	ui.SetShowTotal(false)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/other"})
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasSuffix(lines[0], "/test_dir/nested /subnested"))
+	assert.True(t, strings.HasSuffix(lines[1], "/test_dir/other  big"))
+	assert.Equal(t, "... and 1 more items", lines[2])
+}
+
+func TestMergedRankingWithReports(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/other", os.ModePerm)
+	os.WriteFile("test_dir/other/big", []byte("xxxxxxxxxx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetMergedRanking(true)
+	ui.SetShowDepth(true)
+	ui.SetShowTotal(false)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/other"})
+	assert.Nil(t, err)
+
+	nested, _ := filepath.Abs("test_dir/nested")
+	other, _ := filepath.Abs("test_dir/other")
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Len(t, lines, 11)
+	assert.True(t, strings.HasSuffix(lines[2], "/test_dir/nested file2"))
+	assert.Equal(t, "", lines[3])
+	assert.Equal(t, nested+":", lines[4])
+	assert.Equal(t, "Max depth: 1 ("+nested+"/subnested)", lines[5])
+	assert.Equal(t, "", lines[7])
+	assert.Equal(t, other+":", lines[8])
+	assert.Equal(t, "Max depth: 0 ("+other+")", lines[9])
+}
+
+func TestMergedRankingWithWidePaths(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/文件", os.ModePerm)
+	os.WriteFile("test_dir/文件/big", []byte("xxxxxxxxxx"), 0644)
+	os.MkdirAll("test_dir/abcdefgh", os.ModePerm)
+	os.WriteFile("test_dir/abcdefgh/small", []byte("x"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetMergedRanking(true)
+	ui.SetShowTotal(false)
+	err := ui.AnalyzePaths([]string{"test_dir/文件", "test_dir/abcdefgh"})
+	assert.Nil(t, err)
+
+	// both paths take the same number of columns in the terminal
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.True(t, strings.HasSuffix(lines[0], "/test_dir/文件     big"))
+	assert.True(t, strings.HasSuffix(lines[1], "/test_dir/abcdefgh small"))
+}
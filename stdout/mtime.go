@@ -0,0 +1,40 @@
+package stdout
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+const defaultTimeFormat = "2006-01-02 15:04"
+
+// SetShowMtime sets whether time of last modification of each item should be shown
+func (ui *UI) SetShowMtime(showMtime bool) {
+	ui.showMtime = showMtime
+}
+
+// SetTimeFormat sets layout used for formatting time of last modification (see time.Layout),
+// empty value means the default "2006-01-02 15:04"
+func (ui *UI) SetTimeFormat(format string) {
+	ui.timeFormat = format
+}
+
+// formatMtime returns column with time of last modification of the item
+// (empty for items without known mtime, e.g. empty dirs)
+func (ui *UI) formatMtime(item analyze.Item) string {
+	if !ui.showMtime {
+		return ""
+	}
+
+	layout := ui.timeFormat
+	if layout == "" {
+		layout = defaultTimeFormat
+	}
+
+	mtime := item.GetMtime()
+	if mtime.IsZero() {
+		return strings.Repeat(" ", len(time.Time{}.Format(layout))+1)
+	}
+	return mtime.Local().Format(layout) + " "
+}
@@ -0,0 +1,64 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShowMtime(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	mtime := time.Date(2021, 3, 4, 5, 6, 7, 0, time.Local)
+	os.Chtimes("test_dir/nested/file2", mtime, mtime)
+	os.Chtimes("test_dir/nested/subnested/file", mtime.Add(-time.Hour), mtime.Add(-time.Hour))
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetShowMtime(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	// dirs show the latest mtime of contained items
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    8.0 KiB 2021-03-04 05:06 /nested", lines[0])
+	assert.Equal(t, "    4.0 KiB 2021-03-04 04:06   /subnested", lines[1])
+	assert.Equal(t, "        5 B 2021-03-04 04:06     file", lines[2])
+	assert.Equal(t, "        2 B 2021-03-04 05:06   file2", lines[3])
+}
+
+func TestShowMtimeWithTimeFormat(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	mtime := time.Date(2021, 3, 4, 5, 6, 7, 0, time.Local)
+	os.Chtimes("test_dir/nested/file2", mtime, mtime)
+	os.Chtimes("test_dir/nested/subnested/file", mtime, mtime)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetShowMtime(true)
+	ui.SetTimeFormat(time.RFC3339)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    8.0 KiB "+mtime.Format(time.RFC3339)+" /nested", lines[0])
+}
+
+func TestFormatMtimeOfEmptyDir(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	dir := &analyze.Dir{File: &analyze.File{Name: "empty"}}
+	assert.Equal(t, "", ui.formatMtime(dir))
+
+	ui.SetShowMtime(true)
+	assert.Equal(t, strings.Repeat(" ", 17), ui.formatMtime(dir))
+}
@@ -0,0 +1,26 @@
+package stdout
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetNamePattern sets glob pattern (e.g. "*.mp4") matching names of printed items.
+// Dirs are printed also if they contain at least one matching item.
+// The total counts only matching files when the pattern is set.
+func (ui *UI) SetNamePattern(pattern string) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid name pattern %s: %w", pattern, err)
+	}
+	ui.namePattern = pattern
+	return nil
+}
+
+// matchesName returns true if name of the item itself matches the name pattern,
+// contents of dirs are matched by matchesFilters
+func (ui *UI) matchesName(item analyze.Item) bool {
+	matched, _ := filepath.Match(ui.namePattern, item.GetName())
+	return matched
+}
@@ -0,0 +1,111 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamePattern(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/nested/movie.mp4", []byte("movie"), 0644)
+	os.WriteFile("test_dir/clip.mp4", []byte("clip"), 0644)
+	os.WriteFile("test_dir/notes.txt", []byte("notes"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	err := ui.SetNamePattern("*.mp4")
+	assert.Nil(t, err)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "/nested\n")
+	assert.Contains(t, output.String(), "movie.mp4\n")
+	assert.Contains(t, output.String(), "clip.mp4\n")
+	assert.NotContains(t, output.String(), "notes.txt")
+	assert.NotContains(t, output.String(), "subnested")
+	assert.NotContains(t, output.String(), "file2")
+	assert.Contains(t, output.String(), "Total of matching files: 9 B, 2 items")
+}
+
+func TestNamePatternWithoutMatch(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetNamePattern("*.mp4")
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Equal(t, "Total of matching files: 0 B, 0 items\n", output.String())
+}
+
+func TestNamePatternMatchingDir(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetNamePattern("sub*")
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "/nested\n")
+	assert.Contains(t, output.String(), "/subnested\n")
+	assert.NotContains(t, output.String(), " file\n")
+}
+
+func TestNamePatternMatchingDirInTotal(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/nested/.subhidden", os.ModePerm)
+	os.WriteFile("test_dir/nested/.subhidden/file", []byte("hidden"), 0644)
+	os.WriteFile("test_dir/nested/subnested/file3", []byte("world"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetNamePattern("sub*")
+	ui.SetShowHidden(false)
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	// the total equals the size of the matched dir, the hidden one is left out
+	assert.Contains(t, output.String(), "    4.0 KiB   /subnested\n")
+	assert.NotContains(t, output.String(), ".subhidden")
+	assert.Contains(t, output.String(), "Total of matching files: 4.0 KiB, 3 items")
+}
+
+func TestNamePatternWithExtensions(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/nested/movie.mp4", []byte("movie"), 0644)
+	os.WriteFile("test_dir/nested/report.log", []byte("log"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	err := ui.SetNamePattern("movie*")
+	assert.Nil(t, err)
+	ui.SetIncludeExtensions([]string{"log"})
+	ui.AnalyzePath("test_dir", nil)
+
+	// the name and the extension are matched by different files
+	assert.Equal(t, "Total of matching files: 0 B, 0 items\n", output.String())
+}
+
+func TestInvalidNamePattern(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetNamePattern("[")
+	assert.Equal(t, "invalid name pattern [: syntax error in pattern", err.Error())
+}
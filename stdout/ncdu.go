@@ -0,0 +1,82 @@
+package stdout
+
+import (
+	"bufio"
+	"encoding/json"
+	"time"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/build"
+)
+
+type ncduHeader struct {
+	Progname  string `json:"progname"`
+	Progver   string `json:"progver"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type ncduInfo struct {
+	Name      string `json:"name"`
+	Asize     int64  `json:"asize"`
+	Dsize     *int64 `json:"dsize,omitempty"`
+	ReadError bool   `json:"read_error,omitempty"`
+}
+
+// printNcdu writes whole analyzed tree in the ncdu export format
+func (ui *UI) printNcdu(dir *analyze.Dir) error {
+	w := bufio.NewWriter(ui.output)
+
+	header, err := json.Marshal(ncduHeader{
+		Progname:  "gdu",
+		Progver:   build.Version,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.WriteString("[1,0,")
+	w.Write(header)
+	w.WriteString(",\n")
+
+	if err := ui.writeNcduItem(w, dir, dir.GetPath()); err != nil {
+		return err
+	}
+
+	w.WriteString("]\n")
+	return w.Flush()
+}
+
+func (ui *UI) writeNcduItem(w *bufio.Writer, item analyze.Item, name string) error {
+	info := ncduInfo{
+		Name:      name,
+		Asize:     item.GetSize(),
+		ReadError: item.GetFlag() == '!',
+	}
+	if !ui.showApparentSize {
+		usage := item.GetUsage()
+		info.Dsize = &usage
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	dir, ok := item.(*analyze.Dir)
+	if !ok {
+		_, err = w.Write(data)
+		return err
+	}
+
+	w.WriteByte('[')
+	w.Write(data)
+	for _, file := range ui.sortedFiles(dir.Files) {
+		w.WriteString(",\n")
+		if err := ui.writeNcduItem(w, file, file.GetName()); err != nil {
+			return err
+		}
+	}
+	_, err = w.WriteString("]")
+	return err
+}
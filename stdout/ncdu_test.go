@@ -0,0 +1,141 @@
+package stdout
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+type ncduNode struct {
+	name     string
+	asize    int64
+	dsize    int64
+	hasDsize bool
+	isDir    bool
+	children []*ncduNode
+}
+
+// parseNcdu is minimal parser of the ncdu export format
+func parseNcdu(data []byte) (map[string]interface{}, *ncduNode, error) {
+	var export []json.RawMessage
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, nil, err
+	}
+	if len(export) != 4 {
+		return nil, nil, errors.New("export must have 4 elements")
+	}
+
+	var major, minor int
+	if err := json.Unmarshal(export[0], &major); err != nil || major != 1 {
+		return nil, nil, errors.New("unsupported major version")
+	}
+	if err := json.Unmarshal(export[1], &minor); err != nil {
+		return nil, nil, err
+	}
+
+	var header map[string]interface{}
+	if err := json.Unmarshal(export[2], &header); err != nil {
+		return nil, nil, err
+	}
+
+	root, err := parseNcduNode(export[3])
+	return header, root, err
+}
+
+func parseNcduNode(data json.RawMessage) (*ncduNode, error) {
+	node := &ncduNode{}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err == nil {
+		if len(items) == 0 {
+			return nil, errors.New("directory without info block")
+		}
+		node.isDir = true
+		data = items[0]
+		for _, item := range items[1:] {
+			child, err := parseNcduNode(item)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+	}
+
+	var info map[string]interface{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	name, ok := info["name"].(string)
+	if !ok {
+		return nil, errors.New("item without name")
+	}
+	node.name = name
+	if asize, ok := info["asize"].(float64); ok {
+		node.asize = int64(asize)
+	}
+	if dsize, ok := info["dsize"].(float64); ok {
+		node.dsize = int64(dsize)
+		node.hasDsize = true
+	}
+	return node, nil
+}
+
+func TestAnalyzePathNcdu(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetOutputFormat(NcduOutput)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	header, root, err := parseNcdu(output.Bytes())
+	assert.Nil(t, err)
+
+	assert.Equal(t, "gdu", header["progname"])
+	assert.Contains(t, header, "timestamp")
+
+	assert.Equal(t, "test_dir", root.name)
+	assert.True(t, root.isDir)
+	assert.Len(t, root.children, 4)
+	assert.Equal(t, "aaa", root.children[0].name)
+	assert.True(t, root.children[0].isDir)
+	assert.Equal(t, int64(1<<40+2), root.children[0].asize)
+	assert.Equal(t, int64(1<<40+1), root.children[0].dsize)
+	assert.Equal(t, "ddd", root.children[3].name)
+	assert.False(t, root.children[3].isDir)
+}
+
+func TestAnalyzePathNcduRoundTrip(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(NcduOutput)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	_, root, err := parseNcdu(output.Bytes())
+	assert.Nil(t, err)
+
+	assert.Contains(t, root.name, "test_dir")
+	assert.False(t, root.hasDsize)
+
+	nested := root.children[0]
+	assert.Equal(t, "nested", nested.name)
+	assert.Len(t, nested.children, 2)
+	assert.Equal(t, "subnested", nested.children[0].name)
+	assert.Equal(t, "file", nested.children[0].children[0].name)
+	assert.Equal(t, int64(5), nested.children[0].children[0].asize)
+	assert.Equal(t, "file2", nested.children[1].name)
+	assert.Equal(t, int64(2), nested.children[1].asize)
+	assert.False(t, nested.children[1].isDir)
+}
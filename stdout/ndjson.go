@@ -0,0 +1,46 @@
+package stdout
+
+import (
+	"encoding/json"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+type ndjsonItem struct {
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Usage     int64  `json:"usage"`
+	IsDir     bool   `json:"isDir"`
+	ItemCount int    `json:"itemCount"`
+}
+
+// printNDJSON writes one JSON object per line for each printed item as it is visited,
+// so that the whole tree does not need to be encoded in memory.
+// Nested items are written after their parent dir when the tree is printed recursively.
+func (ui *UI) printNDJSON(dir *analyze.Dir) error {
+	return ui.printNDJSONItems(json.NewEncoder(ui.output), dir.Files, 1)
+}
+
+func (ui *UI) printNDJSONItems(encoder *json.Encoder, items analyze.Files, depth int) error {
+	files, _ := ui.selectFiles(items)
+
+	for _, file := range files {
+		err := encoder.Encode(&ndjsonItem{
+			Path:      file.GetPath(),
+			Size:      file.GetSize(),
+			Usage:     file.GetUsage(),
+			IsDir:     file.IsDir(),
+			ItemCount: file.GetItemCount(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if subdir, ok := file.(*analyze.Dir); ok && ui.shouldExpand(depth) {
+			if err := ui.printNDJSONItems(encoder, subdir.Files, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package stdout
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzePathNDJSON(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetOutputFormat(NDJSONOutput)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 4)
+
+	var item ndjsonItem
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), &item))
+	assert.Equal(t, ndjsonItem{Path: "test_dir/aaa", Size: 1<<40 + 2, Usage: 1<<40 + 1, IsDir: true, ItemCount: 5}, item)
+
+	assert.Nil(t, json.Unmarshal([]byte(lines[3]), &item))
+	assert.Equal(t, ndjsonItem{Path: "test_dir/ddd", Size: 1026, Usage: 1025, ItemCount: 1}, item)
+}
+
+func TestAnalyzePathNDJSONRecursive(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(NDJSONOutput)
+	ui.SetRecursive(true)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 4)
+
+	root, _ := filepath.Abs("test_dir")
+	paths := make([]string, 0, len(lines))
+	for _, line := range lines {
+		var item ndjsonItem
+		assert.Nil(t, json.Unmarshal([]byte(line), &item))
+		path, _ := filepath.Rel(root, item.Path)
+		paths = append(paths, path)
+	}
+	assert.Equal(t, []string{
+		"nested",
+		"nested/subnested",
+		"nested/subnested/file",
+		"nested/file2",
+	}, paths)
+}
@@ -0,0 +1,36 @@
+package stdout
+
+import (
+	"fmt"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetNullSeparated sets that only full paths of listed items are printed, each terminated by NUL byte,
+// so that the output can be safely passed to `xargs -0` whatever characters the names contain.
+// Sizes, colors, headers and totals are not printed; sorting, filters, depth and files-only mode still apply.
+// Unlike in the listing, no "... and N more items" line is printed for items hidden by SetMaxEntries.
+func (ui *UI) SetNullSeparated(nullSeparated bool) {
+	ui.nullSeparated = nullSeparated
+}
+
+// printNullSeparated prints NUL terminated paths of the items in the same order as printItems lists them
+func (ui *UI) printNullSeparated(items analyze.Files, depth int) {
+	files, _ := ui.selectFiles(items)
+
+	for _, file := range files {
+		fmt.Fprintf(ui.output, "%s\x00", file.GetPath())
+
+		if file.IsDir() && ui.shouldExpand(depth) {
+			ui.printNullSeparated(file.(*analyze.Dir).Files, depth+1)
+		}
+	}
+
+	if ui.filesOnly && ui.shouldExpand(depth) {
+		for _, item := range ui.sortedFiles(items) {
+			if subdir, ok := item.(*analyze.Dir); ok && (ui.showHidden || !isHidden(subdir.GetName())) {
+				ui.printNullSeparated(subdir.Files, depth+1)
+			}
+		}
+	}
+}
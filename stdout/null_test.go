@@ -0,0 +1,80 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullSeparated(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/name with\nnewline", []byte("x"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, true, false, true)
+	ui.SetNullSeparated(true)
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	out := output.String()
+	assert.True(t, strings.HasSuffix(out, "\x00"))
+	assert.NotContains(t, out, "\x1b[")
+	assert.NotContains(t, out, "Total")
+	assert.NotContains(t, out, "KiB")
+
+	paths := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	abs, _ := filepath.Abs("test_dir")
+	for i, path := range paths {
+		paths[i], _ = filepath.Rel(abs, path)
+	}
+	assert.Equal(t, []string{
+		"nested",
+		"nested/subnested",
+		"nested/subnested/file",
+		"nested/file2",
+		"name with\nnewline",
+	}, paths)
+}
+
+func TestNullSeparatedFilesOnly(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetNullSeparated(true)
+	ui.SetFilesOnly(true)
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	paths := strings.Split(strings.TrimSuffix(output.String(), "\x00"), "\x00")
+	assert.Len(t, paths, 2)
+	assert.True(t, strings.HasSuffix(paths[0], "/test_dir/nested/file2"))
+	assert.True(t, strings.HasSuffix(paths[1], "/test_dir/nested/subnested/file"))
+}
+
+func TestNullSeparatedPaths(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetNullSeparated(true)
+	ui.SetShowDuration(true)
+	err := ui.AnalyzePaths([]string{"test_dir", "test_dir/nested"})
+	assert.Nil(t, err)
+
+	out := output.String()
+	assert.NotContains(t, out, "\n")
+	assert.Equal(t, 3, strings.Count(out, "\x00"))
+}
@@ -0,0 +1,39 @@
+package stdout
+
+import "github.com/dundee/gdu/v4/analyze"
+
+// SetShowOverhead sets whether overhead of each item (disk usage exceeding the apparent size,
+// caused mostly by rounding of files to whole filesystem blocks) should be shown in a column and after the total.
+// Overhead of a dir is the sum of overheads of all items in it, so sparse files do not hide the wasted space.
+func (ui *UI) SetShowOverhead(showOverhead bool) {
+	ui.showOverhead = showOverhead
+}
+
+// collectOverheads computes overhead of all items in the tree and returns overhead of the item
+func (ui *UI) collectOverheads(item analyze.Item) int64 {
+	overhead := item.GetUsage() - item.GetSize()
+	if dir, ok := item.(*analyze.Dir); ok {
+		overhead = 0
+		for _, file := range dir.Files {
+			overhead += ui.collectOverheads(file)
+		}
+	}
+	if overhead < 0 {
+		overhead = 0
+	}
+	ui.overheads[item] = overhead
+	return overhead
+}
+
+// formatOverhead returns column with overhead of the item
+func (ui *UI) formatOverhead(item analyze.Item) string {
+	if !ui.showOverhead {
+		return ""
+	}
+
+	width := ui.sizeWidth()
+	if ui.rawBytes {
+		width = ui.rawBytesWidth()
+	}
+	return padLeft(ui.formatItemSize(ui.overheads[item]), width) + " "
+}
@@ -0,0 +1,78 @@
+package stdout
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+// smallFilesAnalyzer returns dir with small files each occupying a whole 4 KiB block
+type smallFilesAnalyzer struct {
+	testanalyze.MockedAnalyzer
+}
+
+func (a *smallFilesAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnored) *analyze.Dir {
+	dir := &analyze.Dir{
+		File: &analyze.File{
+			Name: "test_dir",
+		},
+		BasePath: ".",
+	}
+	subdir := &analyze.Dir{
+		File: &analyze.File{
+			Name:   "small",
+			Parent: dir,
+		},
+	}
+	subdir.Files = analyze.Files{
+		&analyze.File{Name: "a", Size: 100, Usage: 4096, Parent: subdir},
+		&analyze.File{Name: "b", Size: 1, Usage: 4096, Parent: subdir},
+	}
+	dir.Files = analyze.Files{
+		subdir,
+		&analyze.File{Name: "full", Size: 8192, Usage: 8192, Parent: dir},
+		&analyze.File{Name: "sparse", Size: 1 << 20, Usage: 0, Parent: dir},
+	}
+	dir.UpdateStats(make(analyze.AlreadyCountedHardlinks))
+
+	return dir
+}
+
+func TestShowOverhead(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowOverhead(true)
+	ui.SetRawBytes(true)
+	ui.SetRecursive(true)
+	ui.analyzer = &smallFilesAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, "            12288             8091 /small", lines[0][1:])
+	assert.Equal(t, "             4096             3996   a", lines[1][1:])
+	assert.Equal(t, "             4096             4095   b", lines[2][1:])
+	assert.Equal(t, "             8192                0 full", lines[3][1:])
+	assert.Equal(t, "                0                0 sparse", lines[4][1:])
+	assert.Equal(t, "Overhead: 8091", lines[len(lines)-1])
+}
+
+func TestShowOverheadInTotal(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowOverhead(true)
+	ui.analyzer = &smallFilesAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, "  12.0 KiB   7.9 KiB /small", lines[0][1:])
+	assert.Equal(t, "Overhead: 7.9 KiB", lines[len(lines)-1])
+}
@@ -0,0 +1,201 @@
+package stdout
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// AnalyzePaths analyzes recursively disk usage in all given paths.
+// Result of each path is printed in separate section followed by grand total of all paths
+// (grand total is printed in text format only, unless disabled by SetShowTotal).
+// Grand total is the sum of totals of the paths, so it counts only matching files
+// when the totals do (see SetTotalMatchingOnly).
+// Failure of one path does not abort analysis of the others.
+// Threshold set by SetFailOver is compared with the grand total.
+// Items of all paths are printed in one ranking instead of the sections if SetMergedRanking is set.
+// In other formats the results of all paths make one document: a JSON array of the dirs
+// or one table (CSV, TSV and Markdown) with rows of all paths. NDJSON and flat formats simply list the paths
+// one after another, ncdu, XML and HTML formats support only one path.
+func (ui *UI) AnalyzePaths(paths []string) error {
+	var (
+		totalSize  int64
+		totalCount int
+		errs       []string
+		merged     []*analyze.Dir
+		reports    []string
+	)
+
+	if len(paths) > 1 && !joinsOutputOfPaths(ui.outputFormat) {
+		return fmt.Errorf("analysis of multiple paths is not supported in %s format", ui.outputFormat)
+	}
+
+	mergesPaths := ui.mergedRanking && ui.printsText()
+	// results are printed in one document after all paths are analyzed
+	joinsPaths := len(paths) > 1 && !ui.quiet && ui.outputFormat != TextOutput &&
+		ui.outputFormat != NDJSONOutput && ui.outputFormat != FlatOutput
+	for i, path := range paths {
+		if i > 0 && ui.printsText() && !mergesPaths {
+			fmt.Fprintln(ui.output)
+		}
+
+		abspath, _ := filepath.Abs(path)
+		if ui.printsText() && !mergesPaths {
+			fmt.Fprintf(ui.output, "%s:\n", abspath)
+		}
+
+		dir, err := ui.analyzePath(context.Background(), path)
+		if err == nil {
+			err = ui.checkUnreadableDirs(dir)
+		}
+		if err != nil {
+			errs = append(errs, err.Error())
+			if ui.printsText() {
+				fmt.Fprintf(ui.output, "Error: %s\n", err.Error())
+			}
+			continue
+		}
+
+		if mergesPaths || joinsPaths {
+			merged = append(merged, dir)
+		} else if err := ui.printAnalyzedDir(dir); err != nil {
+			return err
+		}
+		if mergesPaths {
+			// reports are printed after the ranking, they are made now while the state of the analysis is kept
+			output := ui.output
+			report := &strings.Builder{}
+			ui.output = report
+			err = ui.printReports(dir)
+			ui.output = output
+			if report.Len() > 0 {
+				reports = append(reports, fmt.Sprintf("\n%s:\n%s", abspath, report.String()))
+			}
+		} else {
+			err = ui.printReports(dir)
+		}
+		if err != nil {
+			return err
+		}
+
+		size, count := ui.getTotal(dir)
+		totalSize += size
+		totalCount += count
+	}
+
+	if mergesPaths {
+		ui.printMergedRanking(merged)
+		for _, report := range reports {
+			fmt.Fprint(ui.output, report)
+		}
+	}
+	if joinsPaths {
+		if err := ui.printJoinedDirs(merged); err != nil {
+			return err
+		}
+	}
+
+	if ui.printsText() && ui.showTotal {
+		fmt.Fprintf(
+			ui.output,
+			"\nGrand total: %s, %s items\n",
+			ui.formatSize(totalSize),
+			ui.formatCount(int64(totalCount)),
+		)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf(
+			"analysis of %d of %d paths failed: %s",
+			len(errs), len(paths), strings.Join(errs, "; "),
+		)
+	}
+	return ui.checkFailOver(totalSize)
+}
+
+// joinsOutputOfPaths returns true if results of more paths can be printed in the format
+func joinsOutputOfPaths(format OutputFormat) bool {
+	switch format {
+	case NcduOutput, XMLOutput, HTMLOutput:
+		return false
+	}
+	return true
+}
+
+// printJoinedDirs prints results of more paths as one document
+func (ui *UI) printJoinedDirs(dirs []*analyze.Dir) error {
+	switch ui.outputFormat {
+	case JSONOutput:
+		return ui.printJSONDirs(dirs)
+	case CSVOutput:
+		return ui.printCSV(dirs...)
+	case MarkdownOutput:
+		return ui.printMarkdown(dirs...)
+	case TSVOutput:
+		return ui.printTSV(dirs...)
+	}
+	return nil
+}
+
+// AnalyzePathsFromReader reads paths separated by newline from given reader and analyzes them by AnalyzePaths.
+// Blank lines and lines starting with "#" are skipped.
+// Paths which cannot be accessed are reported and the rest is analyzed anyway.
+func (ui *UI) AnalyzePathsFromReader(input io.Reader) error {
+	paths, err := readPaths(input)
+	if err != nil {
+		return fmt.Errorf("reading paths: %w", err)
+	}
+
+	validPaths := make([]string, 0, len(paths))
+	var errs []string
+	for _, path := range paths {
+		if _, err := ui.pathChecker(path); err != nil {
+			errs = append(errs, err.Error())
+			if ui.printsText() {
+				fmt.Fprintf(ui.output, "Error: %s\n", err.Error())
+			}
+			continue
+		}
+		validPaths = append(validPaths, path)
+	}
+
+	if len(validPaths) == 0 {
+		if len(errs) > 0 {
+			return fmt.Errorf("no valid paths to analyze: %s", strings.Join(errs, "; "))
+		}
+		return errors.New("no paths to analyze")
+	}
+
+	if len(errs) > 0 && ui.printsText() {
+		fmt.Fprintln(ui.output)
+	}
+
+	err = ui.AnalyzePaths(validPaths)
+	if len(errs) > 0 {
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%d of %d paths are invalid: %s", len(paths)-len(validPaths), len(paths), strings.Join(errs, "; "))
+	}
+	return err
+}
+
+func readPaths(input io.Reader) ([]string, error) {
+	var paths []string
+
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	return paths, scanner.Err()
+}
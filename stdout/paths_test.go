@@ -0,0 +1,234 @@
+package stdout
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzePaths(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/other", os.ModePerm)
+	os.WriteFile("test_dir/other/file", []byte("xxx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/other"})
+	assert.Nil(t, err)
+
+	nested, _ := filepath.Abs("test_dir/nested")
+	other, _ := filepath.Abs("test_dir/other")
+
+	assert.Contains(t, output.String(), nested+":\n")
+	assert.Contains(t, output.String(), other+":\n")
+	assert.Contains(t, output.String(), "Total: 8.0 KiB, 4 items")
+	assert.Contains(t, output.String(), "Total: 4.0 KiB, 2 items")
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	// 4096 * 2 + 5 + 2 (nested) + 4096 + 3 (other)
+	assert.Equal(t, "Grand total: 12.0 KiB, 6 items", lines[len(lines)-1])
+}
+
+func TestAnalyzePathsWithoutTotal(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetShowTotal(false)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/nested/subnested"})
+	assert.Nil(t, err)
+
+	assert.NotContains(t, output.String(), "Total")
+	assert.NotContains(t, output.String(), "Grand total")
+	assert.Contains(t, output.String(), "/subnested")
+}
+
+func TestAnalyzePathsWithMatchingTotal(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/other", os.ModePerm)
+	os.WriteFile("test_dir/other/file.log", []byte("xxx"), 0644)
+	os.WriteFile("test_dir/nested/file.log", []byte("xxxxx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetIncludeExtensions([]string{"log"})
+	ui.SetTotalMatchingOnly(true)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/other"})
+	assert.Nil(t, err)
+
+	// the grand total is the sum of the totals of the paths
+	assert.Contains(t, output.String(), "Total of matching files: 5 B, 1 items")
+	assert.Contains(t, output.String(), "Total of matching files: 3 B, 1 items")
+	assert.True(t, strings.HasSuffix(output.String(), "\nGrand total: 8 B, 2 items\n"))
+}
+
+func TestAnalyzePathsWithNonExistingPath(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/xxx", "test_dir/nested/subnested"})
+
+	assert.Equal(t, 1, strings.Count(output.String(), "Error:"))
+	assert.Equal(t, 2, strings.Count(output.String(), "Total:"))
+	assert.Contains(t, output.String(), "Grand total: 12.0 KiB, 6 items")
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "analysis of 1 of 3 paths failed")
+	assert.Contains(t, err.Error(), "xxx")
+}
+
+func TestAnalyzePathsWithMockedAnalyzer(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePaths([]string{"aaa", "bbb"})
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Equal(t, "Grand total: 2.0 TiB, 24 items", lines[len(lines)-1])
+}
+
+func TestAnalyzePathsInJSONFormat(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(JSONOutput)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/nested/subnested"})
+	assert.Nil(t, err)
+
+	var dirs []map[string]interface{}
+	err = json.Unmarshal(output.Bytes(), &dirs)
+	assert.Nil(t, err)
+	assert.Len(t, dirs, 2)
+
+	nested, _ := filepath.Abs("test_dir/nested")
+	subnested, _ := filepath.Abs("test_dir/nested/subnested")
+	assert.Equal(t, nested, dirs[0]["name"])
+	assert.Equal(t, subnested, dirs[1]["name"])
+	assert.Len(t, dirs[0]["children"], 2)
+	assert.Len(t, dirs[1]["children"], 1)
+	assert.NotContains(t, output.String(), "Grand total")
+}
+
+func TestAnalyzePathsInCSVFormat(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(CSVOutput)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/nested/subnested"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, strings.Count(output.String(), "path,size"))
+
+	rows, err := csv.NewReader(output).ReadAll()
+	assert.Nil(t, err)
+	// one header and rows of both paths
+	assert.Len(t, rows, 4)
+}
+
+func TestAnalyzePathsInMarkdownFormat(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(MarkdownOutput)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/nested/subnested"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, strings.Count(output.String(), "| Path |"))
+	assert.Equal(t, 1, strings.Count(output.String(), "**Total**"))
+	assert.Contains(t, output.String(), "| **Total** | 12.0 KiB | 6 |")
+}
+
+func TestAnalyzePathsInUnsupportedFormat(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(XMLOutput)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/nested/subnested"})
+
+	assert.Equal(t, "analysis of multiple paths is not supported in xml format", err.Error())
+	assert.Empty(t, output.String())
+
+	err = ui.AnalyzePaths([]string{"test_dir/nested"})
+	assert.Nil(t, err)
+}
+
+func TestAnalyzePathsFromReader(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/other", os.ModePerm)
+	os.WriteFile("test_dir/other/file", []byte("xxx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+	input := strings.NewReader("# dirs to analyze\ntest_dir/nested\n\n  test_dir/other  \n")
+
+	ui := CreateStdoutUI(output, false, false, true)
+	err := ui.AnalyzePathsFromReader(input)
+	assert.Nil(t, err)
+
+	nested, _ := filepath.Abs("test_dir/nested")
+	other, _ := filepath.Abs("test_dir/other")
+	assert.Contains(t, output.String(), nested+":\n")
+	assert.Contains(t, output.String(), other+":\n")
+	assert.Contains(t, output.String(), "Grand total: 12.0 KiB, 6 items")
+	assert.NotContains(t, output.String(), "#")
+}
+
+func TestAnalyzePathsFromReaderWithInvalidPath(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+	input := strings.NewReader("test_dir/nested\ntest_dir/missing\n")
+
+	ui := CreateStdoutUI(output, false, false, true)
+	err := ui.AnalyzePathsFromReader(input)
+
+	assert.Equal(t, "1 of 2 paths are invalid: stat test_dir/missing: no such file or directory", err.Error())
+	assert.Contains(t, output.String(), "Error: stat test_dir/missing: no such file or directory\n")
+	assert.Contains(t, output.String(), "Grand total: 8.0 KiB, 4 items")
+}
+
+func TestAnalyzePathsFromReaderWithoutValidPaths(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, true)
+
+	err := ui.AnalyzePathsFromReader(strings.NewReader("# nothing\n\n"))
+	assert.Equal(t, "no paths to analyze", err.Error())
+
+	err = ui.AnalyzePathsFromReader(strings.NewReader("missing\n"))
+	assert.Equal(t, "no valid paths to analyze: stat missing: no such file or directory", err.Error())
+}
@@ -0,0 +1,56 @@
+package stdout
+
+import (
+	"fmt"
+	"strings"
+)
+
+const percentBarWidth = 10
+
+// SetShowPercentBars sets whether share of each item in the size of its parent dir
+// should be shown as percentage and bar graph
+func (ui *UI) SetShowPercentBars(showPercentBars bool) {
+	ui.showPercentBars = showPercentBars
+}
+
+// formatPercentBar returns column with share of the size in the parent size (e.g. "[####      ]  42% ")
+func (ui *UI) formatPercentBar(size, parentSize int64) string {
+	if !ui.showPercentBars {
+		return ""
+	}
+
+	var percent float64
+	if parentSize > 0 {
+		percent = float64(size) / float64(parentSize) * 100
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	width := ui.getPercentBarWidth()
+	filled := int(percent/100*float64(width) + 0.5)
+	return fmt.Sprintf(
+		"[%s%s] %3.0f%% ",
+		strings.Repeat("#", filled),
+		strings.Repeat(" ", width-filled),
+		percent,
+	)
+}
+
+// SetShowRootPercent sets whether share of each item in the total size of the analyzed dir should be shown
+func (ui *UI) SetShowRootPercent(showRootPercent bool) {
+	ui.showRootPercent = showRootPercent
+}
+
+// formatRootPercent returns column with share of the size in the size of the analyzed dir (e.g. " 42.0% ")
+func (ui *UI) formatRootPercent(size int64) string {
+	if !ui.showRootPercent {
+		return ""
+	}
+
+	var percent float64
+	if ui.rootSize > 0 {
+		percent = float64(size) / float64(ui.rootSize) * 100
+	}
+	return fmt.Sprintf("%5.1f%% ", percent)
+}
@@ -0,0 +1,120 @@
+package stdout
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatPercentBar(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	assert.Equal(t, "", ui.formatPercentBar(42, 100))
+
+	ui.SetShowPercentBars(true)
+	assert.Equal(t, "[          ]   0% ", ui.formatPercentBar(0, 100))
+	assert.Equal(t, "[####      ]  42% ", ui.formatPercentBar(42, 100))
+	assert.Equal(t, "[#####     ]  50% ", ui.formatPercentBar(1, 2))
+	assert.Equal(t, "[##########] 100% ", ui.formatPercentBar(100, 100))
+	assert.Equal(t, "[          ]   0% ", ui.formatPercentBar(10, 0))
+}
+
+func TestPercentBarLengthScales(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetShowPercentBars(true)
+
+	prev := -1
+	for size := int64(0); size <= 100; size += 10 {
+		filled := strings.Count(ui.formatPercentBar(size, 100), "#")
+		assert.Equal(t, int(size/10), filled)
+		assert.Greater(t, filled, prev)
+		prev = filled
+	}
+}
+
+func TestShowPercentBars(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowPercentBars(true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   1.0 TiB [##########] 100% /aaa", lines[0][1:])
+	assert.Equal(t, "   1.0 GiB [          ]   0% /bbb", lines[1][1:])
+	assert.Equal(t, "   1.0 KiB [          ]   0% ddd", lines[3][1:])
+}
+
+func TestShowPercentBarsRecursive(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetShowPercentBars(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	// percentage is computed against the parent dir
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    4.0 KiB [#####     ]  50%   /subnested", lines[1])
+	assert.Equal(t, "        5 B [          ]   0%     file", lines[2])
+	assert.Equal(t, "        2 B [          ]   0%   file2", lines[3])
+}
+
+func TestFormatRootPercent(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.rootSize = 200
+	assert.Equal(t, "", ui.formatRootPercent(42))
+
+	ui.SetShowRootPercent(true)
+	assert.Equal(t, " 21.0% ", ui.formatRootPercent(42))
+	assert.Equal(t, "100.0% ", ui.formatRootPercent(200))
+
+	ui.rootSize = 0
+	assert.Equal(t, "  0.0% ", ui.formatRootPercent(10))
+}
+
+func TestShowRootPercent(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetShowRootPercent(true)
+	ui.SetShowPercentBars(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	// share in the analyzed dir is printed next to the share in the parent dir
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    4.0 KiB [#####     ]  50%  33.4%   /subnested", lines[1])
+}
+
+func TestRootPercentSumsToHundred(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetShowRootPercent(true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	var sum float64
+	for _, line := range lines[:len(lines)-1] {
+		var percent float64
+		_, err := fmt.Sscanf(strings.Fields(line[strings.Index(line, "B ")+2:])[0], "%f%%", &percent)
+		assert.Nil(t, err)
+		sum += percent
+	}
+	assert.InDelta(t, 100, sum, 0.5)
+}
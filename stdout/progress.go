@@ -0,0 +1,293 @@
+package stdout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	defaultProgressInterval      = 100 * time.Millisecond
+	defaultPlainProgressInterval = time.Second
+	defaultSpinnerFrames         = `⠇⠏⠋⠙⠹⠸⠼⠴⠦⠧`
+	asciiSpinnerFrames           = `|/-\`
+	defaultSpinnerMessage        = "Scanning... Total items: {items} size: {size}"
+	defaultPlainMessage          = "Scanned {items} items, {size}"
+)
+
+// ProgressMode defines how progress of the analysis is shown
+type ProgressMode int
+
+const (
+	// ProgressAuto shows spinner on terminal and plain lines otherwise
+	ProgressAuto ProgressMode = iota
+	// ProgressSpinner rewrites single line with a spinner
+	ProgressSpinner
+	// ProgressPlain prints newline-terminated status lines
+	ProgressPlain
+	// ProgressJSON prints JSON objects with the progress, one per line
+	ProgressJSON
+)
+
+var progressModeNames = map[string]ProgressMode{
+	"auto":    ProgressAuto,
+	"spinner": ProgressSpinner,
+	"plain":   ProgressPlain,
+	"json":    ProgressJSON,
+}
+
+// ParseProgressMode returns progress mode with given name
+func ParseProgressMode(name string) (ProgressMode, error) {
+	mode, ok := progressModeNames[strings.ToLower(name)]
+	if !ok {
+		return ProgressAuto, fmt.Errorf("unknown progress mode: %s", name)
+	}
+	return mode, nil
+}
+
+// SetProgressOutput sets writer for progress of the analysis (stderr by default)
+func (ui *UI) SetProgressOutput(output io.Writer) {
+	ui.progressOutput = output
+}
+
+// SetProgressInterval sets how often the progress is refreshed
+// (0 means default of 100ms, 1s for plain and JSON progress)
+func (ui *UI) SetProgressInterval(interval time.Duration) {
+	ui.progressInterval = interval
+}
+
+// SetProgressMode sets how progress is shown.
+// ProgressAuto uses plain lines when the progress output is not a terminal.
+// ProgressJSON is shown also with output formats other than text, as it does not mix with the results.
+func (ui *UI) SetProgressMode(mode ProgressMode) {
+	ui.progressMode = mode
+}
+
+// SetSpinnerFrames sets characters cycled by the spinner progress, each character is one frame
+// (e.g. `|/-\` for terminals without braille glyphs), empty string means the default braille spinner
+// (or ASCII one if set by SetASCIIOnly)
+func (ui *UI) SetSpinnerFrames(frames string) {
+	ui.spinnerFrames = frames
+}
+
+// SetASCIIOnly sets whether only ASCII characters should be used for drawing (e.g. of the spinner),
+// for terminals and log files without Unicode support
+func (ui *UI) SetASCIIOnly(asciiOnly bool) {
+	ui.asciiOnly = asciiOnly
+}
+
+// SetProgressMessage sets template of the progress message, {items} is replaced by number of analyzed items,
+// {size} by their size and {item} by the currently analyzed path.
+// Empty template means the default message of the progress mode.
+func (ui *UI) SetProgressMessage(template string) {
+	ui.progressMessage = template
+}
+
+// formatProgressMessage expands the template set by SetProgressMessage (or the default one) with current progress
+func (ui *UI) formatProgressMessage(defaultTemplate, items string) string {
+	template := defaultTemplate
+	if ui.progressMessage != "" {
+		template = ui.progressMessage
+	}
+	return strings.NewReplacer(
+		"{items}", items,
+		"{size}", ui.formatSize(ui.progress.TotalSize),
+		"{item}", ui.progress.CurrentItemName,
+	).Replace(template)
+}
+
+// SetProgressCallback sets function receiving progress of the analysis in place of the built-in progress,
+// so that the progress can be rendered elsewhere (e.g. when the UI is embedded in another program).
+// The callback is called with the refresh interval set by SetProgressInterval (100ms by default)
+// even when showing of progress is disabled.
+func (ui *UI) SetProgressCallback(callback func(analyze.CurrentProgress)) {
+	ui.progressCallback = callback
+}
+
+func (ui *UI) usePlainProgress() bool {
+	switch ui.progressMode {
+	case ProgressPlain:
+		return true
+	case ProgressSpinner:
+		return false
+	}
+
+	f, ok := ui.progressOutput.(*os.File)
+	if !ok {
+		return false
+	}
+	return !isatty.IsTerminal(f.Fd()) && !isatty.IsCygwinTerminal(f.Fd())
+}
+
+func (ui *UI) getProgressInterval(defaultInterval time.Duration) time.Duration {
+	if ui.progressInterval <= 0 {
+		return defaultInterval
+	}
+	return ui.progressInterval
+}
+
+func (ui *UI) printPartialTotal() {
+	fmt.Fprintf(
+		ui.output,
+		"Partial total: %s, %s items\n",
+		ui.formatSize(ui.progress.TotalSize),
+		ui.formatCount(int64(ui.progress.ItemCount)),
+	)
+}
+
+func (ui *UI) updateProgress(ctx context.Context) {
+	if ui.progressCallback != nil {
+		ui.updateProgressCallback(ctx)
+		return
+	}
+	if ui.progressMode == ProgressJSON {
+		ui.updateJSONProgress(ctx)
+		return
+	}
+	if ui.usePlainProgress() {
+		ui.updatePlainProgress(ctx)
+		return
+	}
+	ui.updateSpinnerProgress(ctx)
+}
+
+func (ui *UI) updateSpinnerProgress(ctx context.Context) {
+	emptyRow := "\r"
+	for j := 0; j < 100; j++ {
+		emptyRow += " "
+	}
+
+	frames := ui.spinnerFrames
+	if frames == "" {
+		frames = defaultSpinnerFrames
+		if ui.asciiOnly {
+			frames = asciiSpinnerFrames
+		}
+	}
+	progressRunes := []rune(frames)
+
+	progressChan := ui.analyzer.GetProgressChan()
+	doneChan := ui.analyzer.GetDoneChan()
+
+	interval := ui.getProgressInterval(defaultProgressInterval)
+	start := time.Now()
+
+	i := 0
+	for {
+		fmt.Fprint(ui.progressOutput, emptyRow)
+
+		select {
+		case ui.progress = <-progressChan:
+		case <-doneChan:
+			fmt.Fprint(ui.progressOutput, "\r")
+			return
+		case <-ctx.Done():
+			fmt.Fprint(ui.progressOutput, "\r")
+			return
+		}
+
+		fmt.Fprintf(ui.progressOutput, "\r %s ", string(progressRunes[i]))
+
+		fmt.Fprint(ui.progressOutput,
+			ui.formatProgressMessage(defaultSpinnerMessage, ui.red.Sprint(ui.progress.ItemCount))+
+				ui.formatProgressRate(ui.progress, time.Since(start)))
+
+		<-ui.after(interval)
+		i++
+		i %= len(progressRunes)
+	}
+}
+
+// refreshProgress calls refresh with each progress received from the analyzer, at most once per the interval,
+// until the analysis is done, ctx is cancelled or refresh fails (e.g. when the progress output is closed)
+func (ui *UI) refreshProgress(ctx context.Context, interval time.Duration, refresh func() error) {
+	progressChan := ui.analyzer.GetProgressChan()
+	doneChan := ui.analyzer.GetDoneChan()
+
+	for {
+		select {
+		case ui.progress = <-progressChan:
+		case <-doneChan:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		if err := refresh(); err != nil {
+			return
+		}
+
+		// the interval can be long so finish as soon as the analysis is done
+		select {
+		case <-doneChan:
+			return
+		case <-ctx.Done():
+			return
+		case <-ui.after(interval):
+		}
+	}
+}
+
+func (ui *UI) updatePlainProgress(ctx context.Context) {
+	start := time.Now()
+
+	ui.refreshProgress(ctx, ui.getProgressInterval(defaultPlainProgressInterval), func() error {
+		_, err := fmt.Fprintln(
+			ui.progressOutput,
+			ui.formatProgressMessage(defaultPlainMessage, strconv.Itoa(ui.progress.ItemCount))+
+				ui.formatProgressRate(ui.progress, time.Since(start)),
+		)
+		return err
+	})
+}
+
+type jsonProgress struct {
+	ItemCount   int     `json:"itemCount"`
+	TotalSize   int64   `json:"totalSize"`
+	CurrentItem string  `json:"currentItem"`
+	Elapsed     float64 `json:"elapsed"`
+}
+
+func (ui *UI) updateJSONProgress(ctx context.Context) {
+	encoder := json.NewEncoder(ui.progressOutput)
+	start := time.Now()
+
+	ui.refreshProgress(ctx, ui.getProgressInterval(defaultPlainProgressInterval), func() error {
+		return encoder.Encode(&jsonProgress{
+			ItemCount:   ui.progress.ItemCount,
+			TotalSize:   ui.progress.TotalSize,
+			CurrentItem: ui.progress.CurrentItemName,
+			Elapsed:     time.Since(start).Seconds(),
+		})
+	})
+}
+
+func (ui *UI) updateProgressCallback(ctx context.Context) {
+	ui.refreshProgress(ctx, ui.getProgressInterval(defaultProgressInterval), func() error {
+		ui.progressCallback(ui.progress)
+		return nil
+	})
+}
+
+// formatProgressRate returns number of items and bytes analyzed per second and elapsed time
+func (ui *UI) formatProgressRate(progress analyze.CurrentProgress, elapsed time.Duration) string {
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		" (%.f items/s, %s/s) elapsed: %s",
+		float64(progress.ItemCount)/seconds,
+		ui.formatSize(int64(float64(progress.TotalSize)/seconds)),
+		elapsed.Round(time.Second),
+	)
+}
@@ -0,0 +1,370 @@
+package stdout
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatProgressRate(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	progress := analyze.CurrentProgress{ItemCount: 3000, TotalSize: 30 << 20}
+	assert.Equal(t, " (1000 items/s, 10.0 MiB/s) elapsed: 3s", ui.formatProgressRate(progress, 3*time.Second))
+
+	progress = analyze.CurrentProgress{ItemCount: 90, TotalSize: 90 << 10}
+	assert.Equal(t, " (1 items/s, 1.0 KiB/s) elapsed: 1m30s", ui.formatProgressRate(progress, 90*time.Second))
+
+	assert.Equal(t, "", ui.formatProgressRate(progress, 0))
+}
+
+func TestProgressShowsRate(t *testing.T) {
+	progressOutput := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, true, false)
+	ui.SetProgressOutput(progressOutput)
+	ui.analyzer = newProgressAnalyzer()
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Contains(t, progressOutput.String(), "items/s")
+	assert.Contains(t, progressOutput.String(), "B/s")
+	assert.Contains(t, progressOutput.String(), "elapsed: 0s")
+}
+
+// continuousProgressAnalyzer keeps reporting progress for given duration
+type continuousProgressAnalyzer struct {
+	progressAnalyzer
+	duration time.Duration
+}
+
+func (a *continuousProgressAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnored) *analyze.Dir {
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case a.progressChan <- analyze.CurrentProgress{CurrentItemName: path, ItemCount: 1, TotalSize: 1}:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	time.Sleep(a.duration)
+	close(stop)
+	a.doneChan <- struct{}{}
+	return a.MockedAnalyzer.AnalyzeDir(path, ignore)
+}
+
+// steppedProgressAnalyzer reports given number of progress updates, each one is received before the next is sent
+// (updates not received within 200ms are dropped)
+type steppedProgressAnalyzer struct {
+	progressAnalyzer
+	steps int
+}
+
+func newSteppedProgressAnalyzer(steps int) *steppedProgressAnalyzer {
+	a := &steppedProgressAnalyzer{progressAnalyzer: *newProgressAnalyzer(), steps: steps}
+	a.progressChan = make(chan analyze.CurrentProgress)
+	return a
+}
+
+func (a *steppedProgressAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnored) *analyze.Dir {
+	for i := 1; i <= a.steps; i++ {
+		select {
+		case a.progressChan <- analyze.CurrentProgress{CurrentItemName: path, ItemCount: i, TotalSize: 1}:
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+	a.doneChan <- struct{}{}
+	return a.MockedAnalyzer.AnalyzeDir(path, ignore)
+}
+
+// getProgressWaits returns number of progress refreshes and intervals waited for between them
+func getProgressWaits(t *testing.T, interval time.Duration) (int, []time.Duration) {
+	var (
+		mutex sync.Mutex
+		waits []time.Duration
+	)
+	progressOutput := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, true, false)
+	ui.SetProgressOutput(progressOutput)
+	ui.SetProgressInterval(interval)
+	ui.after = func(d time.Duration) <-chan time.Time {
+		mutex.Lock()
+		waits = append(waits, d)
+		mutex.Unlock()
+
+		elapsed := make(chan time.Time, 1)
+		elapsed <- time.Time{}
+		return elapsed
+	}
+	ui.analyzer = newSteppedProgressAnalyzer(3)
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	return strings.Count(progressOutput.String(), "Scanning..."), waits
+}
+
+func TestProgressInterval(t *testing.T) {
+	refreshes, waits := getProgressWaits(t, 10*time.Millisecond)
+	assert.Equal(t, 3, refreshes)
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond}, waits)
+}
+
+func TestDefaultProgressInterval(t *testing.T) {
+	refreshes, waits := getProgressWaits(t, 0)
+	assert.Equal(t, 3, refreshes)
+	assert.Equal(t, []time.Duration{defaultProgressInterval, defaultProgressInterval, defaultProgressInterval}, waits)
+}
+
+func TestPlainProgress(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+	progressOutput := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, true, false)
+	ui.SetProgressOutput(progressOutput)
+	ui.SetProgressMode(ProgressPlain)
+	ui.SetProgressInterval(10 * time.Millisecond)
+	ui.analyzer = &continuousProgressAnalyzer{
+		progressAnalyzer: *newProgressAnalyzer(),
+		duration:         50 * time.Millisecond,
+	}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Contains(t, progressOutput.String(), "Scanned ")
+	assert.True(t, strings.HasSuffix(progressOutput.String(), "\n"))
+	assert.NotContains(t, progressOutput.String(), "\r")
+	assert.NotContains(t, output.String(), "\r")
+}
+
+func TestAutoProgressModeWithFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "gdu-progress")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, true, false)
+	ui.SetProgressOutput(f)
+	ui.analyzer = newProgressAnalyzer()
+	ui.pathChecker = testdir.MockedPathChecker
+	err = ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	content, err := ioutil.ReadFile(f.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, "Scanned 7 items, 1.0 KiB", strings.SplitN(string(content), " (", 2)[0])
+	assert.NotContains(t, string(content), "\r")
+}
+
+func TestSpinnerProgressModeWithFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "gdu-progress")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, true, false)
+	ui.SetProgressOutput(f)
+	ui.SetProgressMode(ProgressSpinner)
+
+	assert.False(t, ui.usePlainProgress())
+}
+
+func TestParseProgressMode(t *testing.T) {
+	mode, err := ParseProgressMode("Plain")
+	assert.Nil(t, err)
+	assert.Equal(t, ProgressPlain, mode)
+
+	mode, err = ParseProgressMode("json")
+	assert.Nil(t, err)
+	assert.Equal(t, ProgressJSON, mode)
+
+	_, err = ParseProgressMode("fancy")
+	assert.Equal(t, "unknown progress mode: fancy", err.Error())
+}
+
+// increasingProgressAnalyzer reports growing progress before the analysis is done
+type increasingProgressAnalyzer struct {
+	progressAnalyzer
+}
+
+func (a *increasingProgressAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnored) *analyze.Dir {
+	for i := 1; i <= 5; i++ {
+		a.progressChan <- analyze.CurrentProgress{CurrentItemName: path, ItemCount: i, TotalSize: int64(i) << 10}
+	}
+	time.Sleep(20 * time.Millisecond)
+	a.doneChan <- struct{}{}
+	return a.MockedAnalyzer.AnalyzeDir(path, ignore)
+}
+
+func TestProgressCallback(t *testing.T) {
+	progressOutput := bytes.NewBuffer(nil)
+	var progresses []analyze.CurrentProgress
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetProgressOutput(progressOutput)
+	ui.SetProgressInterval(time.Millisecond)
+	ui.SetProgressCallback(func(progress analyze.CurrentProgress) {
+		progresses = append(progresses, progress)
+	})
+	ui.analyzer = &increasingProgressAnalyzer{*newProgressAnalyzer()}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, progresses)
+	for i := 1; i < len(progresses); i++ {
+		assert.Greater(t, progresses[i].ItemCount, progresses[i-1].ItemCount)
+		assert.Greater(t, progresses[i].TotalSize, progresses[i-1].TotalSize)
+	}
+	assert.Equal(t, "", progressOutput.String())
+}
+
+func TestJSONProgress(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+	progressOutput := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, true, false)
+	ui.SetOutputFormat(JSONOutput)
+	ui.SetProgressOutput(progressOutput)
+	ui.SetProgressMode(ProgressJSON)
+	ui.SetProgressInterval(time.Millisecond)
+	ui.analyzer = &increasingProgressAnalyzer{*newProgressAnalyzer()}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(progressOutput.String(), "\n"), "\n")
+	assert.NotEmpty(t, lines)
+
+	var previous jsonProgress
+	for _, line := range lines {
+		var progress jsonProgress
+		assert.Nil(t, json.Unmarshal([]byte(line), &progress), line)
+		assert.Greater(t, progress.ItemCount, previous.ItemCount)
+		assert.Greater(t, progress.TotalSize, previous.TotalSize)
+		assert.GreaterOrEqual(t, progress.Elapsed, previous.Elapsed)
+		assert.Equal(t, "test_dir", filepath.Base(progress.CurrentItem))
+		previous = progress
+	}
+
+	// results are not mixed with the progress
+	assert.True(t, json.Valid(output.Bytes()))
+}
+
+// failingWriter counts writes, all of them fail
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("broken pipe")
+}
+
+func TestJSONProgressStopsOnWriteError(t *testing.T) {
+	progressOutput := &failingWriter{}
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, true, false)
+	ui.SetOutputFormat(JSONOutput)
+	ui.SetProgressOutput(progressOutput)
+	ui.SetProgressMode(ProgressJSON)
+	ui.after = func(d time.Duration) <-chan time.Time {
+		elapsed := make(chan time.Time, 1)
+		elapsed <- time.Time{}
+		return elapsed
+	}
+	ui.analyzer = newSteppedProgressAnalyzer(3)
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, progressOutput.writes)
+}
+
+func TestCustomSpinnerFrames(t *testing.T) {
+	progressOutput := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, true, false)
+	ui.SetProgressOutput(progressOutput)
+	ui.SetProgressMode(ProgressSpinner)
+	ui.SetProgressInterval(5 * time.Millisecond)
+	ui.SetSpinnerFrames(`|/-\`)
+	ui.analyzer = &continuousProgressAnalyzer{
+		progressAnalyzer: *newProgressAnalyzer(),
+		duration:         100 * time.Millisecond,
+	}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	var frames []string
+	for _, part := range strings.Split(progressOutput.String(), "\r ")[1:] {
+		if part != "" && !strings.HasPrefix(part, " ") {
+			frames = append(frames, part[:1])
+		}
+	}
+	assert.GreaterOrEqual(t, len(frames), 5)
+	for i, frame := range frames {
+		assert.Equal(t, string(`|/-\`[i%4]), frame)
+	}
+}
+
+func TestProgressMessage(t *testing.T) {
+	progressOutput := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, true, false)
+	ui.SetProgressOutput(progressOutput)
+	ui.SetProgressMode(ProgressPlain)
+	ui.SetProgressMessage("{items} files of {size} in {item}")
+	ui.analyzer = newProgressAnalyzer()
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(progressOutput.String(), "7 files of 1.0 KiB in "))
+	assert.Contains(t, progressOutput.String(), "test_dir")
+}
+
+func TestASCIIOnly(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+	progressOutput := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, true, true)
+	ui.SetProgressOutput(progressOutput)
+	ui.SetProgressMode(ProgressSpinner)
+	ui.SetASCIIOnly(true)
+	ui.SetRecursive(true)
+	ui.SetShowPercentBars(true)
+	ui.analyzer = &continuousProgressAnalyzer{
+		progressAnalyzer: *newProgressAnalyzer(),
+		duration:         50 * time.Millisecond,
+	}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	assert.Contains(t, progressOutput.String(), "Scanning...")
+	for _, r := range output.String() + progressOutput.String() {
+		if r > unicode.MaxASCII {
+			t.Fatalf("non-ASCII character %q in output", r)
+		}
+	}
+}
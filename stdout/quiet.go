@@ -0,0 +1,13 @@
+package stdout
+
+// SetQuiet sets whether listing of items, progress and totals should be suppressed,
+// so that only errors are reported (e.g. when only saving the scan or checking the threshold set by SetFailOver)
+func (ui *UI) SetQuiet(quiet bool) {
+	ui.quiet = quiet
+}
+
+// printsText returns true if the results are printed in text format
+// (and not suppressed by quiet mode or replaced by null-separated paths)
+func (ui *UI) printsText() bool {
+	return ui.outputFormat == TextOutput && !ui.quiet && !ui.nullSeparated
+}
@@ -0,0 +1,63 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuiet(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+	scan := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, true, true)
+	ui.SetQuiet(true)
+	ui.SetScanOutput(scan)
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", output.String())
+
+	// the scan is saved anyway
+	dir, err := analyze.LoadScan(scan)
+	assert.Nil(t, err)
+	assert.Equal(t, "test_dir", dir.GetName())
+}
+
+func TestQuietJSON(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(JSONOutput)
+	ui.SetQuiet(true)
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", output.String())
+}
+
+func TestQuietPaths(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/other", os.ModePerm)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetQuiet(true)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/other", "test_dir/xxx"})
+
+	assert.Contains(t, err.Error(), "analysis of 1 of 3 paths failed")
+	assert.Equal(t, "", output.String())
+}
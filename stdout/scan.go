@@ -0,0 +1,22 @@
+package stdout
+
+import (
+	"io"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetScanOutput sets writer the analyzed tree is saved to by AnalyzePath
+// (nil means the tree is not saved)
+func (ui *UI) SetScanOutput(output io.Writer) {
+	ui.scanOutput = output
+}
+
+// PrintScan loads dir tree saved by analyze.SaveScan and prints it the same way as AnalyzePath
+func (ui *UI) PrintScan(input io.Reader) error {
+	dir, err := analyze.LoadScan(input)
+	if err != nil {
+		return err
+	}
+	return ui.printAnalyzedDir(dir)
+}
@@ -0,0 +1,138 @@
+package stdout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+var sizeSuffixes = map[string]float64{
+	"":    1,
+	"B":   1,
+	"K":   1 << 10,
+	"KIB": 1 << 10,
+	"M":   1 << 20,
+	"MIB": 1 << 20,
+	"G":   1 << 30,
+	"GIB": 1 << 30,
+	"T":   1 << 40,
+	"TIB": 1 << 40,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+}
+
+// ParseSize parses human readable size like "10M", "1.5GiB" or "200KB".
+// Single letter and *iB suffixes are binary (powers of 1024), *B suffixes are decimal.
+// Error is returned for sizes not fitting in int64.
+func ParseSize(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+
+	i := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(value)
+	}
+
+	// too big numbers are parsed as infinity with range error
+	number, err := strconv.ParseFloat(value[:i], 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) || number < 0 {
+		return 0, fmt.Errorf("invalid size: %s", value)
+	}
+
+	multiplier, ok := sizeSuffixes[strings.ToUpper(strings.TrimSpace(value[i:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid size: %s", value)
+	}
+
+	// float64 of math.MaxInt64 is rounded up to 2^63, which does not fit anymore
+	size := number * multiplier
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size too big: %s", value)
+	}
+	return int64(size), nil
+}
+
+// SizeUnit defines unit which all formatted sizes are converted to
+type SizeUnit int
+
+const (
+	// UnitAuto chooses the unit by magnitude of each size
+	UnitAuto SizeUnit = iota
+	// UnitB shows sizes in bytes
+	UnitB
+	// UnitK shows sizes in KiB (or KB with SI prefixes)
+	UnitK
+	// UnitM shows sizes in MiB (or MB with SI prefixes)
+	UnitM
+	// UnitG shows sizes in GiB (or GB with SI prefixes)
+	UnitG
+	// UnitT shows sizes in TiB (or TB with SI prefixes)
+	UnitT
+)
+
+var sizeUnitNames = map[string]SizeUnit{
+	"auto": UnitAuto,
+	"b":    UnitB,
+	"k":    UnitK,
+	"kib":  UnitK,
+	"kb":   UnitK,
+	"m":    UnitM,
+	"mib":  UnitM,
+	"mb":   UnitM,
+	"g":    UnitG,
+	"gib":  UnitG,
+	"gb":   UnitG,
+	"t":    UnitT,
+	"tib":  UnitT,
+	"tb":   UnitT,
+}
+
+// ParseSizeUnit returns size unit with given name (e.g. "M", "GiB" or "auto").
+// Whether binary or decimal prefix is used depends only on the SI setting of the UI.
+func ParseSizeUnit(name string) (SizeUnit, error) {
+	unit, ok := sizeUnitNames[strings.ToLower(name)]
+	if !ok {
+		return UnitAuto, fmt.Errorf("unknown size unit: %s", name)
+	}
+	return unit, nil
+}
+
+// SetForceUnit sets unit which all sizes are shown in (UnitAuto chooses the unit for each size)
+func (ui *UI) SetForceUnit(unit SizeUnit) {
+	ui.forceUnit = unit
+}
+
+// SetShowBothSizes sets whether apparent size should be printed next to disk usage
+// (or disk usage next to apparent size when apparent size is shown),
+// which helps to spot sparse files and block overhead
+func (ui *UI) SetShowBothSizes(showBothSizes bool) {
+	ui.showBothSizes = showBothSizes
+}
+
+// getOtherSize returns disk usage of the item if apparent size is shown and apparent size otherwise
+func (ui *UI) getOtherSize(item analyze.Item) int64 {
+	if ui.showApparentSize {
+		return item.GetUsage()
+	}
+	return item.GetSize()
+}
+
+// formatOtherSize returns column with the size not used for sorting and filtering of the item
+func (ui *UI) formatOtherSize(item analyze.Item) string {
+	if !ui.showBothSizes {
+		return ""
+	}
+
+	width := ui.sizeWidth()
+	if ui.rawBytes {
+		width = ui.rawBytesWidth()
+	}
+	return padLeft(ui.formatItemSize(ui.getOtherSize(item)), width) + " "
+}
@@ -0,0 +1,171 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{
+		"0":       0,
+		"100":     100,
+		"100B":    100,
+		"1K":      1 << 10,
+		"1k":      1 << 10,
+		"10M":     10 << 20,
+		"1.5G":    3 << 29,
+		"2T":      2 << 40,
+		"10MiB":   10 << 20,
+		"10 MiB":  10 << 20,
+		"10MB":    10e6,
+		"1kb":     1000,
+		"3GB":     3e9,
+		" 512K  ": 512 << 10,
+	}
+
+	for value, expected := range tests {
+		size, err := ParseSize(value)
+		assert.Nil(t, err, value)
+		assert.Equal(t, expected, size, value)
+	}
+}
+
+func TestParseSizeWithErr(t *testing.T) {
+	for _, value := range []string{"", "M", "10X", "1.2.3K", "-5M", "ten"} {
+		_, err := ParseSize(value)
+		assert.Equal(t, "invalid size: "+value, err.Error())
+	}
+}
+
+func TestParseSizeTooBig(t *testing.T) {
+	for _, value := range []string{"9223372036854775808", "8388608T", "10000000TB", "1" + strings.Repeat("0", 400)} {
+		_, err := ParseSize(value)
+		assert.Equal(t, "size too big: "+value, err.Error())
+	}
+
+	size, err := ParseSize("8388607T")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(8388607)<<40, size)
+}
+
+func TestParseSizeUnit(t *testing.T) {
+	tests := map[string]SizeUnit{
+		"auto": UnitAuto,
+		"B":    UnitB,
+		"k":    UnitK,
+		"MiB":  UnitM,
+		"GB":   UnitG,
+		"t":    UnitT,
+	}
+
+	for name, expected := range tests {
+		unit, err := ParseSizeUnit(name)
+		assert.Nil(t, err, name)
+		assert.Equal(t, expected, unit, name)
+	}
+
+	_, err := ParseSizeUnit("PiB")
+	assert.Equal(t, "unknown size unit: PiB", err.Error())
+}
+
+func TestFormatSizeWithForcedUnit(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	ui.SetForceUnit(UnitM)
+	assert.Equal(t, "0.0 MiB", ui.formatSize(0))
+	assert.Equal(t, "0.0 MiB", ui.formatSize(1023))
+	assert.Equal(t, "0.5 MiB", ui.formatSize(1<<19))
+	assert.Equal(t, "1.0 MiB", ui.formatSize(1<<20))
+	assert.Equal(t, "1048576.0 MiB", ui.formatSize(1<<40))
+
+	ui.SetForceUnit(UnitB)
+	assert.Equal(t, "1099511627776 B", ui.formatSize(1<<40))
+
+	ui.SetForceUnit(UnitG)
+	ui.SetSizePrecision(2)
+	assert.Equal(t, "0.50 GiB", ui.formatSize(1<<29))
+}
+
+func TestFormatSizeWithForcedSIUnit(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetUseSIPrefix(true)
+	ui.SetForceUnit(UnitK)
+
+	assert.Equal(t, "1.5 KB", ui.formatSize(1500))
+	assert.Equal(t, "2000000.0 KB", ui.formatSize(2e9))
+}
+
+func TestItemRowsWithForcedUnit(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetForceUnit(UnitK)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 5)
+	for _, line := range lines {
+		assert.Contains(t, line, " KiB")
+		assert.NotContains(t, line, "MiB")
+		assert.NotContains(t, line, "GiB")
+		assert.NotContains(t, line, "TiB")
+	}
+	assert.Equal(t, " 1073741824.0 KiB /aaa", lines[0][1:])
+	assert.Equal(t, "          1.0 KiB ddd", lines[3][1:])
+}
+
+func TestShowBothSizes(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowBothSizes(true)
+	ui.SetRawBytes(true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    1099511627777    1099511627778 /aaa", lines[0][1:])
+	assert.Equal(t, "             1025             1026 ddd", lines[3][1:])
+}
+
+func TestShowBothSizesWithApparentSize(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetShowBothSizes(true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   1.0 TiB   1.0 TiB /aaa", lines[0][1:])
+	assert.Equal(t, "   1.0 KiB   1.0 KiB ddd", lines[3][1:])
+}
+
+func TestShowBothSizesOfSparseFile(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	f, _ := os.Create("test_dir/sparse")
+	f.Truncate(1 << 20)
+	f.Close()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowBothSizes(true)
+	ui.SetRawBytes(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "                0          1048576 sparse", lines[1][1:])
+}
@@ -0,0 +1,143 @@
+package stdout
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+var (
+	sortKeys   = []string{"size", "name", "itemCount", "mtime", "none"}
+	sortOrders = []string{"desc", "asc"}
+	groupings  = []string{"", "dirs-first", "files-first"}
+)
+
+// SetSorting sets key (size, name, itemCount or mtime) and order (asc or desc) for sorting of printed items.
+// Key "none" prints items in the order of analysis without sorting (and copying) them,
+// which saves memory for dirs with millions of items. The order and grouping are ignored then.
+func (ui *UI) SetSorting(sortBy string, sortOrder string) error {
+	if !contains(sortKeys, sortBy) {
+		return fmt.Errorf("unknown sort key: %s", sortBy)
+	}
+	if !contains(sortOrders, sortOrder) {
+		return fmt.Errorf("unknown sort order: %s", sortOrder)
+	}
+
+	ui.sortBy = sortBy
+	ui.sortOrder = sortOrder
+	return nil
+}
+
+// SetGrouping sets whether all dirs should be printed before all files ("dirs-first") or after them ("files-first"),
+// items in each group are sorted by the sort settings. Empty grouping means items are not grouped.
+func (ui *UI) SetGrouping(grouping string) error {
+	if !contains(groupings, grouping) {
+		return fmt.Errorf("unknown grouping: %s", grouping)
+	}
+	ui.grouping = grouping
+	return nil
+}
+
+// groupedSorter orders dirs before files (or the other way round) and items of the same kind by the wrapped sorter
+type groupedSorter struct {
+	sort.Interface
+	files     analyze.Files
+	dirsFirst bool
+}
+
+func (s groupedSorter) Less(i, j int) bool {
+	iDir, jDir := s.files[i].IsDir(), s.files[j].IsDir()
+	if iDir != jDir {
+		return iDir == s.dirsFirst
+	}
+	return s.Interface.Less(i, j)
+}
+
+// sortedFiles returns copy of files sorted by current sort settings.
+// Items with equal sort key are ordered by name so the output is deterministic.
+// Files are returned as they are if the sorting is disabled.
+func (ui *UI) sortedFiles(files analyze.Files) analyze.Files {
+	if ui.sortBy == "none" {
+		return files
+	}
+
+	sorted := make(analyze.Files, len(files))
+	copy(sorted, files)
+
+	sort.Stable(sort.Reverse(analyze.ByName(sorted)))
+	sort.Stable(ui.getSorter(sorted))
+	return sorted
+}
+
+func (ui *UI) getSorter(files analyze.Files) sort.Interface {
+	var sorter sort.Interface
+
+	switch ui.sortBy {
+	case "name":
+		sorter = analyze.ByName(files)
+	case "itemCount":
+		sorter = analyze.ByItemCount(files)
+	case "mtime":
+		sorter = analyze.ByMtime(files)
+	default:
+		if ui.showApparentSize {
+			sorter = analyze.ByApparentSize(files)
+		} else {
+			sorter = files
+		}
+	}
+
+	if ui.sortOrder == "asc" {
+		sorter = sort.Reverse(sorter)
+	}
+	if ui.grouping != "" {
+		sorter = groupedSorter{Interface: sorter, files: files, dirsFirst: ui.grouping == "dirs-first"}
+	}
+	return sorter
+}
+
+// topFiles returns the first n files printed in the order of sortedFiles and number of the remaining printed ones.
+// Only n items are kept and sorted instead of the whole dir, so the memory used does not grow with the size of the dir.
+func (ui *UI) topFiles(files analyze.Files, n int) (analyze.Files, int) {
+	pair := make(analyze.Files, 2)
+	sorter := ui.getSorter(pair)
+	// less orders items the same way as the stable sorts in sortedFiles
+	less := func(a, b analyze.Item) bool {
+		pair[0], pair[1] = a, b
+		if sorter.Less(0, 1) {
+			return true
+		}
+		return !sorter.Less(1, 0) && a.GetName() < b.GetName()
+	}
+
+	top := make(analyze.Files, 0, n+1)
+	count := 0
+	for _, file := range files {
+		if !ui.shouldBePrinted(file) {
+			continue
+		}
+		count++
+		if len(top) == n && !less(file, top[n-1]) {
+			continue
+		}
+
+		i := sort.Search(len(top), func(i int) bool { return less(file, top[i]) })
+		top = append(top, nil)
+		copy(top[i+1:], top[i:])
+		top[i] = file
+		if len(top) > n {
+			top = top[:n]
+		}
+	}
+	return top, count - len(top)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
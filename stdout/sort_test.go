@@ -0,0 +1,237 @@
+package stdout
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/stretchr/testify/assert"
+)
+
+func getFilesForSorting() analyze.Files {
+	now := time.Now()
+	return analyze.Files{
+		&analyze.File{Name: "bbb", Size: 3, Usage: 1, Mtime: now.Add(-time.Hour)},
+		&analyze.Dir{File: &analyze.File{Name: "ccc", Size: 1, Usage: 3, Mtime: now}, ItemCount: 5},
+		&analyze.File{Name: "aaa", Size: 2, Usage: 2, Mtime: now.Add(-2 * time.Hour)},
+		&analyze.File{Name: "ddd", Size: 2, Usage: 2, Mtime: now.Add(-2 * time.Hour)},
+	}
+}
+
+func getNames(files analyze.Files) []string {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.GetName())
+	}
+	return names
+}
+
+func TestSortByUsage(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	files := getFilesForSorting()
+
+	assert.Equal(t, []string{"ccc", "aaa", "ddd", "bbb"}, getNames(ui.sortedFiles(files)))
+
+	ui.SetSorting("size", "asc")
+	assert.Equal(t, []string{"bbb", "aaa", "ddd", "ccc"}, getNames(ui.sortedFiles(files)))
+
+	// original slice is not changed
+	assert.Equal(t, "bbb", files[0].GetName())
+}
+
+func TestSortByApparentSize(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, true)
+	files := getFilesForSorting()
+
+	assert.Equal(t, []string{"bbb", "aaa", "ddd", "ccc"}, getNames(ui.sortedFiles(files)))
+
+	ui.SetSorting("size", "asc")
+	assert.Equal(t, []string{"ccc", "aaa", "ddd", "bbb"}, getNames(ui.sortedFiles(files)))
+}
+
+func TestSortByName(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	files := getFilesForSorting()
+
+	ui.SetSorting("name", "asc")
+	assert.Equal(t, []string{"aaa", "bbb", "ccc", "ddd"}, getNames(ui.sortedFiles(files)))
+
+	ui.SetSorting("name", "desc")
+	assert.Equal(t, []string{"ddd", "ccc", "bbb", "aaa"}, getNames(ui.sortedFiles(files)))
+}
+
+func TestSortByItemCount(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	files := getFilesForSorting()
+
+	ui.SetSorting("itemCount", "desc")
+	assert.Equal(t, []string{"ccc", "aaa", "bbb", "ddd"}, getNames(ui.sortedFiles(files)))
+
+	ui.SetSorting("itemCount", "asc")
+	assert.Equal(t, []string{"aaa", "bbb", "ddd", "ccc"}, getNames(ui.sortedFiles(files)))
+}
+
+func TestSortByMtime(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	files := getFilesForSorting()
+
+	ui.SetSorting("mtime", "desc")
+	assert.Equal(t, []string{"ccc", "bbb", "aaa", "ddd"}, getNames(ui.sortedFiles(files)))
+
+	ui.SetSorting("mtime", "asc")
+	assert.Equal(t, []string{"aaa", "ddd", "bbb", "ccc"}, getNames(ui.sortedFiles(files)))
+}
+
+func TestSetSortingWithErr(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	err := ui.SetSorting("color", "asc")
+	assert.Equal(t, "unknown sort key: color", err.Error())
+
+	err = ui.SetSorting("name", "up")
+	assert.Equal(t, "unknown sort order: up", err.Error())
+}
+
+func getFilesForGrouping() analyze.Files {
+	now := time.Now()
+	return analyze.Files{
+		&analyze.File{Name: "bbb", Size: 3, Usage: 3, Mtime: now.Add(-time.Hour)},
+		&analyze.Dir{File: &analyze.File{Name: "ccc", Size: 1, Usage: 1, Mtime: now}, ItemCount: 5},
+		&analyze.File{Name: "aaa", Size: 4, Usage: 4, Mtime: now.Add(-2 * time.Hour)},
+		&analyze.Dir{File: &analyze.File{Name: "ddd", Size: 2, Usage: 2, Mtime: now.Add(-3 * time.Hour)}, ItemCount: 2},
+	}
+}
+
+func TestGroupDirsFirst(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	assert.Nil(t, ui.SetGrouping("dirs-first"))
+	files := getFilesForGrouping()
+
+	tests := []struct {
+		sortBy, sortOrder string
+		expected          []string
+	}{
+		{"size", "desc", []string{"ddd", "ccc", "aaa", "bbb"}},
+		{"size", "asc", []string{"ccc", "ddd", "bbb", "aaa"}},
+		{"name", "asc", []string{"ccc", "ddd", "aaa", "bbb"}},
+		{"name", "desc", []string{"ddd", "ccc", "bbb", "aaa"}},
+		{"itemCount", "desc", []string{"ccc", "ddd", "aaa", "bbb"}},
+		{"mtime", "desc", []string{"ccc", "ddd", "bbb", "aaa"}},
+		{"mtime", "asc", []string{"ddd", "ccc", "aaa", "bbb"}},
+	}
+	for _, test := range tests {
+		ui.SetSorting(test.sortBy, test.sortOrder)
+		assert.Equal(t, test.expected, getNames(ui.sortedFiles(files)), test.sortBy+" "+test.sortOrder)
+	}
+}
+
+func TestGroupFilesFirst(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	assert.Nil(t, ui.SetGrouping("files-first"))
+	files := getFilesForGrouping()
+
+	assert.Equal(t, []string{"aaa", "bbb", "ddd", "ccc"}, getNames(ui.sortedFiles(files)))
+
+	ui.SetSorting("size", "asc")
+	assert.Equal(t, []string{"bbb", "aaa", "ccc", "ddd"}, getNames(ui.sortedFiles(files)))
+
+	ui.SetSorting("name", "asc")
+	assert.Equal(t, []string{"aaa", "bbb", "ccc", "ddd"}, getNames(ui.sortedFiles(files)))
+}
+
+func TestSetUnknownGrouping(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetGrouping("xxx")
+	assert.Equal(t, "unknown grouping: xxx", err.Error())
+}
+
+func TestSortByNone(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetSorting("none", "asc")
+	files := getFilesForSorting()
+
+	assert.Equal(t, []string{"bbb", "ccc", "aaa", "ddd"}, getNames(ui.sortedFiles(files)))
+
+	ui.SetMaxEntries(2)
+	selected, hidden := ui.selectFiles(files)
+	assert.Equal(t, []string{"bbb", "ccc"}, getNames(selected))
+	assert.Equal(t, 2, hidden)
+}
+
+func TestTopFiles(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	now := time.Now()
+	files := append(
+		getFilesForGrouping(),
+		&analyze.File{Name: "eee", Size: 3, Usage: 3, Mtime: now.Add(-time.Hour)},
+		&analyze.Dir{File: &analyze.File{Name: "fff", Size: 2, Usage: 2, Mtime: now}, ItemCount: 5},
+		&analyze.File{Name: "ggg", Size: 1, Usage: 1, Mtime: now.Add(-4 * time.Hour)},
+	)
+
+	settings := []struct{ sortBy, sortOrder, grouping string }{
+		{"size", "desc", ""},
+		{"size", "asc", ""},
+		{"name", "asc", ""},
+		{"itemCount", "desc", "files-first"},
+		{"mtime", "asc", "dirs-first"},
+	}
+	for _, s := range settings {
+		ui.SetSorting(s.sortBy, s.sortOrder)
+		ui.SetGrouping(s.grouping)
+
+		for n := 1; n <= len(files)+1; n++ {
+			ui.SetMaxEntries(n)
+			top, hidden := ui.topFiles(files, n)
+			expected, expectedHidden := ui.limitFiles(ui.sortedFiles(files))
+			assert.Equal(t, getNames(expected), getNames(top), s)
+			assert.Equal(t, expectedHidden, hidden, s)
+		}
+	}
+}
+
+func TestSelectFilesWithoutSortingDoesNotAllocate(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetSorting("none", "desc")
+	files := getFilesForHugeDir(1000)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		ui.selectFiles(files)
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func getFilesForHugeDir(n int) analyze.Files {
+	files := make(analyze.Files, 0, n)
+	for i := 0; i < n; i++ {
+		files = append(files, &analyze.File{Name: fmt.Sprintf("file%d", i), Usage: int64(i*7919%n) * 4096})
+	}
+	return files
+}
+
+func BenchmarkSelectFiles(b *testing.B) {
+	files := getFilesForHugeDir(100000)
+
+	benchmarks := []struct {
+		name       string
+		sortBy     string
+		maxEntries int
+	}{
+		{"sorted", "size", 0},
+		{"top", "size", 10},
+		{"none", "none", 0},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+			ui.SetSorting(bm.sortBy, "desc")
+			ui.SetMaxEntries(bm.maxEntries)
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ui.selectFiles(files)
+			}
+		})
+	}
+}
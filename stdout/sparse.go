@@ -0,0 +1,42 @@
+package stdout
+
+import (
+	"fmt"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// sparseMarker is printed in front of names of sparse files
+const sparseMarker = "S"
+
+// SetSparseRatio sets that files with disk usage smaller than given ratio of their apparent size
+// (e.g. 0.5 for files occupying less than half of their size) are marked as sparse (0 disables marking)
+func (ui *UI) SetSparseRatio(ratio float64) error {
+	if ratio < 0 || ratio > 1 {
+		return fmt.Errorf("sparse ratio must be between 0 and 1: %g", ratio)
+	}
+	ui.sparseRatio = ratio
+	return nil
+}
+
+// isSparse returns true if the item is a file occupying less than sparseRatio of its apparent size
+func (ui *UI) isSparse(item analyze.Item) bool {
+	if item.IsDir() || item.GetSize() <= 0 {
+		return false
+	}
+	return float64(item.GetUsage()) < float64(item.GetSize())*ui.sparseRatio
+}
+
+// formatSparseMarker returns column with marker of sparse files (empty for other items)
+func (ui *UI) formatSparseMarker(item analyze.Item) string {
+	if ui.sparseRatio == 0 {
+		return ""
+	}
+	if !ui.isSparse(item) {
+		return "  "
+	}
+	if ui.useColors {
+		return ui.red.Sprint(sparseMarker) + " "
+	}
+	return sparseMarker + " "
+}
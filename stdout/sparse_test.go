@@ -0,0 +1,51 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparseMarker(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	f, _ := os.Create("test_dir/sparse")
+	f.Truncate(1 << 20)
+	f.Close()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	assert.Nil(t, ui.SetSparseRatio(0.5))
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    1.0 MiB S sparse", lines[0])
+	assert.Equal(t, "    8.0 KiB   /nested", lines[1])
+	assert.Equal(t, "        5 B       file", lines[3])
+}
+
+func TestIsSparse(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetSparseRatio(0.5)
+
+	assert.True(t, ui.isSparse(&analyze.File{Size: 100, Usage: 49}))
+	assert.False(t, ui.isSparse(&analyze.File{Size: 100, Usage: 50}))
+	assert.False(t, ui.isSparse(&analyze.File{Size: 100, Usage: 4096}))
+	assert.False(t, ui.isSparse(&analyze.File{Size: 0, Usage: 0}))
+	assert.False(t, ui.isSparse(&analyze.Dir{File: &analyze.File{Size: 100, Usage: 0}}))
+}
+
+func TestInvalidSparseRatio(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	err := ui.SetSparseRatio(1.5)
+	assert.Equal(t, "sparse ratio must be between 0 and 1: 1.5", err.Error())
+}
@@ -1,13 +1,17 @@
 package stdout
 
 import (
+	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"math"
 	"os"
 	"path/filepath"
-	"sort"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,29 +20,171 @@ import (
 	"github.com/fatih/color"
 )
 
+// maxSizePrecision is maximal number of decimal places of formatted sizes
+const maxSizePrecision = 3
+
+// rawBytesLength is width of the size column with raw bytes (enough for petabytes)
+const rawBytesLength = 16
+
+var (
+	binaryUnits = []string{"KiB", "MiB", "GiB", "TiB"}
+	siUnits     = []string{"KB", "MB", "GB", "TB"}
+	dirMarkers  = []string{"prefix", "suffix", "none"}
+)
+
 // UI struct
 type UI struct {
 	analyzer         analyze.Analyzer
+	analyzerFactory  func() analyze.Analyzer
 	output           io.Writer
+	progressOutput   io.Writer
+	warningOutput    io.Writer
+	progressInterval time.Duration
+	progressMode     ProgressMode
+	scanOutput       io.Writer
 	ignoreDirPaths   map[string]struct{}
+	ignorePatterns   []string
+	ignoreRegexps    []*regexp.Regexp
+	useGitignore     bool
+	gitignore        *gitignoreMatcher
 	useColors        bool
+	colorSize        bool
+	colorDir         bool
+	colorPercent     bool
 	showProgress     bool
+	quiet            bool
+	progressCallback func(analyze.CurrentProgress)
+	spinnerFrames    string
+	asciiOnly        bool
+	progressMessage  string
 	showApparentSize bool
+	useSIPrefixes    bool
+	outputFormat     OutputFormat
+	sortBy           string
+	sortOrder        string
+	grouping         string
+	maxEntries       int
+	largestFiles     int
+	largestDirs      int
+	maxDepth         int
+	minSize          int64
+	olderThan        time.Duration
+	newerThan        time.Duration
+	matchingTotal    bool
+	includeExts      []string
+	excludeExts      []string
+	namePattern      string
+	matchingDirs     map[dirFilters]bool
+	showHidden       bool
+	dirsOnly         bool
+	filesOnly        bool
+	recursive        bool
+	timeout          time.Duration
+	maxConcurrency   int
+	followSymlinks   bool
+	dedupHardlinks   bool
+	failOnReadErrors bool
+	crossFilesystems bool
+	showItemCount    bool
+	showPercentBars  bool
+	showRootPercent  bool
+	rootSize         int64
+	showBothSizes    bool
+	showXattrs       bool
+	xattrSizes       map[analyze.Item]int64
+	showOverhead     bool
+	overheads        map[analyze.Item]int64
+	sparseRatio      float64
+	showMtime        bool
+	showFullPath     bool
+	showRelativePath bool
+	dirMarker        string
+	rootPath         string
+	nameWidth        int
+	nameTruncation   string
+	terminalWidth    int
+	timeFormat       string
+	itemCountWidth   int
+	showInodes       bool
+	deviceColumns    []string
+	showAvail        bool
+	devicesGetter    device.DevicesInfoGetter
+	showDevicesTotal bool
+	digitSeparator   string
+	dfLayout         bool
+	includeFsTypes   []string
+	excludeFsTypes   []string
+	mountPrefix      string
+	deviceSortBy     string
+	deviceSortOrder  string
+	summarizeOnly    bool
+	childTotals      bool
+	showDuration     bool
+	showDepth        bool
+	growthFile       string
+	scanDuration     time.Duration
+	emptyDirs        bool
+	nullSeparated    bool
+	extensionTotals  bool
+	mergedRanking    bool
+	verbose          bool
+	ignoredPaths     []string
+	ignoredParents   map[string]struct{}
+	retriedReads     []string
+	skippedMutex     sync.Mutex
+	readRetries      int
+	retryBackoff     time.Duration
+	zeroSizeAsEmpty  bool
+	duplicates       bool
+	showTotal        bool
+	failOverSize     int64
+	rawBytes         bool
+	sizePrecision    int
+	forceUnit        SizeUnit
+	sizeColumnWidth  int
+	mediumSize       int64
+	largeSize        int64
+	usageWarning     float64
+	usageCritical    float64
+	progress         analyze.CurrentProgress
 	red              *color.Color
 	orange           *color.Color
 	blue             *color.Color
 	pathChecker      func(string) (fs.FileInfo, error)
+	fileHasher       func(string) ([sha256.Size]byte, error)
+	now              func() time.Time
+	after            func(time.Duration) <-chan time.Time
 }
 
-// CreateStdoutUI creates UI for stdout
+// CreateStdoutUI creates UI for stdout.
+// Colors are always disabled when NO_COLOR env variable is set
+// and always enabled when FORCE_COLOR is set (e.g. when the output is piped).
 func CreateStdoutUI(output io.Writer, useColors bool, showProgress bool, showApparentSize bool) *UI {
+	useColors, forceColors := colorsFromEnv(useColors)
+
 	ui := &UI{
 		output:           output,
+		progressOutput:   os.Stderr,
+		warningOutput:    os.Stderr,
 		useColors:        useColors,
 		showProgress:     showProgress,
 		showApparentSize: showApparentSize,
+		sortBy:           "size",
+		sortOrder:        "desc",
+		showHidden:       true,
+		showTotal:        true,
+		colorSize:        true,
+		colorDir:         true,
+		colorPercent:     true,
+		sizePrecision:    1,
+		crossFilesystems: true,
 		analyzer:         analyze.CreateAnalyzer(),
+		analyzerFactory:  analyze.CreateAnalyzer,
+		devicesGetter:    device.Getter,
 		pathChecker:      os.Stat,
+		fileHasher:       hashFile,
+		now:              time.Now,
+		after:            time.After,
 	}
 
 	ui.red = color.New(color.FgRed).Add(color.Bold)
@@ -47,6 +193,8 @@ func CreateStdoutUI(output io.Writer, useColors bool, showProgress bool, showApp
 
 	if !useColors {
 		color.NoColor = true
+	} else if forceColors {
+		color.NoColor = false
 	}
 
 	return ui
@@ -63,60 +211,92 @@ func (ui *UI) ListDevices(getter device.DevicesInfoGetter) error {
 	if err != nil {
 		return err
 	}
+	devices = ui.filterDevices(devices)
+	ui.sortDevices(devices)
 
-	maxDeviceNameLenght := maxInt(maxLength(
-		devices,
-		func(device *device.Device) string { return device.Name },
-	), len("Devices"))
-
-	var sizeLength, percentLength int
-	if ui.useColors {
-		sizeLength = 20
-		percentLength = 16
-	} else {
-		sizeLength = 9
-		percentLength = 5
+	if ui.outputFormat == JSONOutput {
+		return ui.printDevicesJSON(devices)
+	}
+	if ui.dfLayout {
+		ui.printDevicesDf(devices)
+		return nil
+	}
+	if ui.showDevicesTotal {
+		devices = append(devices, getDevicesTotal(devices))
 	}
 
-	lineFormat := fmt.Sprintf(
-		"%%%ds %%%ds %%%ds %%%ds %%%ds %%s\n",
-		maxDeviceNameLenght,
-		sizeLength,
-		sizeLength,
-		sizeLength,
-		percentLength,
-	)
+	var maxSize int64
+	for _, dev := range devices {
+		if dev.Size > maxSize {
+			maxSize = dev.Size
+		}
+	}
+	ui.setSizeColumnWidth(maxSize)
 
-	fmt.Fprintf(
-		ui.output,
-		fmt.Sprintf("%%%ds %%9s %%9s %%9s %%5s %%s\n", maxDeviceNameLenght),
-		"Device",
-		"Size",
-		"Used",
-		"Free",
-		"Used%",
-		"Mount point",
-	)
+	columns := ui.createDeviceColumns(devices)
+	ui.printDeviceRow(columns, nil)
+	for _, dev := range devices {
+		ui.printDeviceRow(columns, dev)
+	}
 
-	for _, device := range devices {
-		usedPercent := math.Round(float64(device.Size-device.Free) / float64(device.Size) * 100)
+	return nil
+}
 
-		fmt.Fprintf(
-			ui.output,
-			lineFormat,
-			device.Name,
-			ui.formatSize(device.Size),
-			ui.formatSize(device.Size-device.Free),
-			ui.formatSize(device.Free),
-			ui.red.Sprintf("%.f%%", usedPercent),
-			device.MountPoint)
+// getUsedPercent returns percentage of used space of the device (0 for device with zero size)
+func getUsedPercent(dev *device.Device) float64 {
+	if dev.Size == 0 {
+		return 0
 	}
+	return float64(dev.Size-dev.Free) / float64(dev.Size) * 100
+}
 
-	return nil
+// SetShowInodes sets whether inode usage of devices should be listed
+func (ui *UI) SetShowInodes(show bool) {
+	ui.showInodes = show
 }
 
 // AnalyzePath analyzes recursively disk usage in given path
-func (ui *UI) AnalyzePath(path string, _ *analyze.Dir) error {
+func (ui *UI) AnalyzePath(path string, parentDir *analyze.Dir) error {
+	return ui.AnalyzePathContext(context.Background(), path, parentDir)
+}
+
+// AnalyzePathContext analyzes recursively disk usage in given path.
+// The analysis is aborted when ctx is cancelled, ctx.Err() is returned in such case.
+func (ui *UI) AnalyzePathContext(ctx context.Context, path string, _ *analyze.Dir) error {
+	dir, err := ui.analyzePath(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := ui.checkUnreadableDirs(dir); err != nil {
+		return err
+	}
+
+	if ui.scanOutput != nil {
+		if err := analyze.SaveScan(dir, ui.scanOutput); err != nil {
+			return err
+		}
+	}
+	if err := ui.printAnalyzedDir(dir); err != nil {
+		return err
+	}
+	if err := ui.printReports(dir); err != nil {
+		return err
+	}
+
+	size, _ := ui.getTotal(dir)
+	return ui.checkFailOver(size)
+}
+
+// printReports prints reports of the last analysis of the dir following its results
+func (ui *UI) printReports(dir *analyze.Dir) error {
+	ui.printUnreadableDirsWarning(dir)
+	ui.printSkippedPaths(dir)
+	ui.printDepth(dir)
+	ui.printScanDuration()
+	return ui.printGrowth(dir)
+}
+
+func (ui *UI) analyzePath(ctx context.Context, path string) (*analyze.Dir, error) {
 	var (
 		dir  *analyze.Dir
 		wait sync.WaitGroup
@@ -125,124 +305,545 @@ func (ui *UI) AnalyzePath(path string, _ *analyze.Dir) error {
 
 	_, err := ui.pathChecker(abspath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if ui.showProgress {
+	if ui.useGitignore {
+		ui.gitignore = newGitignoreMatcher(abspath)
+	}
+
+	if ui.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ui.timeout)
+		defer cancel()
+	}
+
+	showProgress := ui.progressCallback != nil ||
+		ui.showProgress && (ui.printsText() || ui.progressMode == ProgressJSON && !ui.quiet)
+	if showProgress {
 		wait.Add(1)
 		go func() {
 			defer wait.Done()
-			ui.updateProgress()
+			ui.updateProgress(ctx)
 		}()
 	}
 
-	wait.Add(1)
+	// once the context is cancelled, all remaining paths are skipped
+	// so that the analyzer finishes as soon as possible
+	// analyzer abandoned after a timeout can still be running
+	ui.skippedMutex.Lock()
+	ui.ignoredPaths = nil
+	ui.retriedReads = nil
+	ui.skippedMutex.Unlock()
+	ui.matchingDirs = nil
+	ignore := func(path string) bool {
+		if ctx.Err() != nil {
+			return true
+		}
+		if ui.ShouldDirBeIgnored(path) {
+			// dirs with ignored entries are not reported as empty
+			if ui.verbose || ui.emptyDirs {
+				ui.addIgnoredPath(path)
+			}
+			return true
+		}
+		return false
+	}
+	// only .gitignore files can ignore files, no callback is needed without them
+	var ignoreFile analyze.ShouldFileBeIgnored
+	if ui.useGitignore {
+		ignoreFile = func(path string) bool {
+			if ui.ShouldFileBeIgnored(path) {
+				if ui.verbose || ui.emptyDirs {
+					ui.addIgnoredPath(path)
+				}
+				return true
+			}
+			return false
+		}
+	}
+
+	ui.analyzer.SetMaxConcurrency(ui.maxConcurrency)
+	ui.analyzer.SetFollowSymlinks(ui.followSymlinks)
+	ui.analyzer.SetDedupHardlinks(ui.dedupHardlinks)
+	ui.analyzer.SetCrossFilesystems(ui.crossFilesystems)
+	ui.analyzer.SetIgnoreFile(ignoreFile)
+	if ui.verbose {
+		ui.analyzer.SetReadRetries(ui.readRetries, ui.retryBackoff, ui.addRetriedRead, ctx.Done())
+	} else {
+		ui.analyzer.SetReadRetries(ui.readRetries, ui.retryBackoff, nil, ctx.Done())
+	}
+	ui.analyzer.ResetProgress()
+
+	start := time.Now()
+	analyzed := make(chan struct{})
+	analyzer := ui.analyzer
 	go func() {
-		defer wait.Done()
-		dir = ui.analyzer.AnalyzeDir(abspath, ui.ShouldDirBeIgnored)
+		defer close(analyzed)
+		dir = analyzer.AnalyzeDir(abspath, ignore)
 	}()
 
-	wait.Wait()
+	select {
+	case <-analyzed:
+		wait.Wait()
+		if !showProgress {
+			// consume the done signal meant for progress so that the analyzer can be reused
+			select {
+			case <-ui.analyzer.GetDoneChan():
+			default:
+			}
+		}
+		ui.scanDuration = time.Since(start)
+	case <-ctx.Done():
+		wait.Wait()
+		// aborted analyzer can still be running in background, so it cannot be reused
+		ui.analyzer = ui.analyzerFactory()
+
+		if ui.timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			if ui.showProgress && !ui.quiet {
+				ui.printPartialTotal()
+			}
+			return nil, fmt.Errorf("analysis of %s timed out after %s: %w", abspath, ui.timeout, ctx.Err())
+		}
+		return nil, ctx.Err()
+	}
 
-	sort.Sort(dir.Files)
+	return dir, nil
+}
 
-	var lineFormat string
-	if ui.useColors {
-		lineFormat = "%s %20s %s\n"
-	} else {
-		lineFormat = "%s %9s %s\n"
+func (ui *UI) printAnalyzedDir(dir *analyze.Dir) error {
+	if ui.quiet {
+		return nil
 	}
 
-	var size int64
-
-	for _, file := range dir.Files {
-		if ui.showApparentSize {
-			size = file.GetSize()
+	switch ui.outputFormat {
+	case JSONOutput:
+		return ui.printJSON(dir)
+	case NcduOutput:
+		return ui.printNcdu(dir)
+	case CSVOutput:
+		return ui.printCSV(dir)
+	case HTMLOutput:
+		return ui.printHTML(dir)
+	case MarkdownOutput:
+		return ui.printMarkdown(dir)
+	case XMLOutput:
+		return ui.printXML(dir)
+	case TSVOutput:
+		return ui.printTSV(dir)
+	case NDJSONOutput:
+		return ui.printNDJSON(dir)
+	case FlatOutput:
+		return ui.printFlat(dir)
+	default:
+		if ui.nullSeparated {
+			ui.printNullSeparated(dir.Files, 1)
+		} else if ui.largestFiles > 0 || ui.largestDirs > 0 {
+			ui.printLargest(dir)
+		} else if ui.childTotals {
+			ui.printChildTotals(dir)
+		} else if ui.emptyDirs {
+			ui.printEmptyDirs(dir)
+		} else if ui.duplicates {
+			ui.printDuplicates(dir)
+		} else if ui.extensionTotals {
+			ui.printExtensionTotals(dir)
 		} else {
-			size = file.GetUsage()
+			ui.printDir(dir)
 		}
+	}
 
-		if file.IsDir() {
-			fmt.Fprintf(ui.output,
-				lineFormat,
-				string(file.GetFlag()),
-				ui.formatSize(size),
-				ui.blue.Sprintf("/"+file.GetName()))
-		} else {
-			fmt.Fprintf(ui.output,
-				lineFormat,
-				string(file.GetFlag()),
-				ui.formatSize(size),
-				file.GetName())
-		}
+	return nil
+}
+
+// SetAnalyzer sets analyzer used for the analysis of paths (analyze.CreateAnalyzer() by default).
+// The analyzer is used again after an aborted analysis, so it has to handle being reused
+// while the aborted analysis can still be running. Use SetAnalyzerFactory for analyzers which cannot.
+func (ui *UI) SetAnalyzer(analyzer analyze.Analyzer) {
+	ui.analyzer = analyzer
+	ui.analyzerFactory = func() analyze.Analyzer { return analyzer }
+}
+
+// SetAnalyzerFactory sets function creating analyzers used for the analysis of paths (analyze.CreateAnalyzer by default).
+// A new analyzer is created by it after an aborted analysis, as the previous one can still be running.
+func (ui *UI) SetAnalyzerFactory(factory func() analyze.Analyzer) {
+	ui.analyzerFactory = factory
+	ui.analyzer = factory()
+}
+
+// SetTimeout sets maximal duration of the analysis, 0 means no limit
+func (ui *UI) SetTimeout(timeout time.Duration) {
+	ui.timeout = timeout
+}
+
+// SetMaxConcurrency sets maximal number of dirs read concurrently by the analyzer,
+// 0 means the default limit
+func (ui *UI) SetMaxConcurrency(n int) {
+	ui.maxConcurrency = n
+}
+
+// SetFollowSymlinks sets whether symlinks should be followed during the analysis.
+// Content of a dir linked multiple times is then counted multiple times.
+func (ui *UI) SetFollowSymlinks(follow bool) {
+	ui.followSymlinks = follow
+}
+
+// SetDedupHardlinks sets whether size of hardlinked files should be shown
+// only for the first found link, so that sizes of all items sum up to the total
+func (ui *UI) SetDedupHardlinks(dedup bool) {
+	ui.dedupHardlinks = dedup
+}
+
+// SetCrossFilesystems sets whether dirs on other filesystems than the analyzed path
+// should be analyzed too (true by default)
+func (ui *UI) SetCrossFilesystems(cross bool) {
+	ui.crossFilesystems = cross
+}
+
+// SetReadRetries sets how many times reading of a dir failed with a transient error (e.g. on a network mount)
+// is retried before the dir is reported as unreadable, the delay between retries starts at backoff and doubles.
+// Retries are listed in the report of SetVerbose.
+func (ui *UI) SetReadRetries(retries int, backoff time.Duration) error {
+	if retries < 0 {
+		return fmt.Errorf("read retries must not be negative: %d", retries)
 	}
+	ui.readRetries = retries
+	ui.retryBackoff = backoff
+	return nil
+}
 
+// SetDirMarker sets how names of dirs are distinguished from files: prefixed by "/" ("prefix"),
+// followed by "/" ("suffix") or not at all ("none"). Empty marker means the default "prefix".
+func (ui *UI) SetDirMarker(marker string) error {
+	if marker == "" {
+		marker = "prefix"
+	}
+	if !contains(dirMarkers, marker) {
+		return fmt.Errorf("unknown dir marker: %s", marker)
+	}
+	ui.dirMarker = marker
 	return nil
 }
 
-// SetIgnoreDirPaths sets paths to ignore
-func (ui *UI) SetIgnoreDirPaths(paths []string) {
-	ui.ignoreDirPaths = make(map[string]struct{}, len(paths))
-	for _, path := range paths {
-		ui.ignoreDirPaths[path] = struct{}{}
+// SetShowFullPath sets whether full paths of items should be printed instead of names
+func (ui *UI) SetShowFullPath(showFullPath bool) {
+	ui.showFullPath = showFullPath
+}
+
+// SetShowRelativePath sets whether paths of items relative to the analyzed dir
+// should be printed instead of names
+func (ui *UI) SetShowRelativePath(showRelativePath bool) {
+	ui.showRelativePath = showRelativePath
+}
+
+// SetShowItemCount sets whether number of items in each dir should be printed
+func (ui *UI) SetShowItemCount(show bool) {
+	ui.showItemCount = show
+}
+
+// SetSummarizeOnly sets whether only the total should be printed, without listing of items
+func (ui *UI) SetSummarizeOnly(summarize bool) {
+	ui.summarizeOnly = summarize
+}
+
+// SetShowTotal sets whether the total line of the analyzed dir (and the grand total of several dirs)
+// should be printed after the listing of items.
+// Which items the totals count is not set here: the grand total is always the sum of the totals of the dirs,
+// both count all analyzed items or only files matching the filters (see SetTotalMatchingOnly).
+func (ui *UI) SetShowTotal(showTotal bool) {
+	ui.showTotal = showTotal
+}
+
+// SetUseSIPrefix sets whether sizes should be formatted with decimal SI prefixes (KB, MB, GB)
+// instead of binary ones (KiB, MiB, GiB)
+func (ui *UI) SetUseSIPrefix(useSIPrefix bool) {
+	ui.useSIPrefixes = useSIPrefix
+}
+
+// SetSizePrecision sets number of decimal places (0-3) of formatted sizes
+func (ui *UI) SetSizePrecision(precision int) error {
+	if precision < 0 || precision > maxSizePrecision {
+		return fmt.Errorf("size precision must be between 0 and %d: %d", maxSizePrecision, precision)
 	}
+	ui.sizePrecision = precision
+	return nil
 }
 
-// ShouldDirBeIgnored returns true if given path should be ignored
-func (ui *UI) ShouldDirBeIgnored(path string) bool {
-	_, ok := ui.ignoreDirPaths[path]
-	return ok
+// SetRawBytes sets whether sizes should be printed as plain number of bytes without units and colors
+func (ui *UI) SetRawBytes(raw bool) {
+	ui.rawBytes = raw
 }
 
-func (ui *UI) updateProgress() {
-	emptyRow := "\r"
-	for j := 0; j < 100; j++ {
-		emptyRow += " "
+func (ui *UI) printDir(dir *analyze.Dir) {
+	// no item can contain more items than the analyzed dir
+	ui.itemCountWidth = len(ui.formatCount(int64(dir.GetItemCount())))
+	maxSize := ui.getSize(dir)
+	if ui.showBothSizes && ui.getOtherSize(dir) > maxSize {
+		maxSize = ui.getOtherSize(dir)
+	}
+	ui.setSizeColumnWidth(maxSize)
+	ui.rootSize = ui.getSize(dir)
+	ui.rootPath = dir.GetPath()
+	if ui.showXattrs {
+		ui.xattrSizes = make(map[analyze.Item]int64)
+		ui.collectXattrSizes(dir)
+	}
+	if ui.showOverhead {
+		ui.overheads = make(map[analyze.Item]int64)
+		ui.collectOverheads(dir)
+	}
+
+	if !ui.summarizeOnly {
+		ui.printItems(dir.Files, ui.getSize(dir), 1)
+	}
+	if ui.showTotal {
+		ui.printTotal(dir)
+		if ui.showXattrs {
+			fmt.Fprintf(ui.output, "Extended attributes: %s\n", ui.formatSize(ui.xattrSizes[dir]))
+		}
+		if ui.showOverhead {
+			fmt.Fprintf(ui.output, "Overhead: %s\n", ui.formatSize(ui.overheads[dir]))
+		}
 	}
+}
 
-	progressRunes := []rune(`⠇⠏⠋⠙⠹⠸⠼⠴⠦⠧`)
+func (ui *UI) printItems(items analyze.Files, parentSize int64, depth int) {
+	var prefixFormat string
+	switch {
+	case ui.rawBytes:
+		prefixFormat = fmt.Sprintf("%%s %%%ds %%s%%s", ui.rawBytesWidth())
+	case ui.useColors:
+		// size is padded in formatItemSize as it can contain color codes of different lengths
+		prefixFormat = "%s %s %s%s"
+	default:
+		prefixFormat = fmt.Sprintf("%%s %%%ds %%s%%s", ui.sizeWidth())
+	}
 
-	progressChan := ui.analyzer.GetProgressChan()
-	doneChan := ui.analyzer.GetDoneChan()
+	indent := strings.Repeat("  ", depth-1)
+	if ui.showFullPath || ui.showRelativePath || ui.filesOnly {
+		// paths are printed without indentation so that they can be easily parsed
+		indent = ""
+	}
+	files, hidden := ui.selectFiles(items)
 
-	var progress analyze.CurrentProgress
+	for _, file := range files {
+		size := ui.getSize(file)
 
-	i := 0
-	for {
-		fmt.Fprint(ui.output, emptyRow)
+		prefix := fmt.Sprintf(prefixFormat,
+			string(file.GetFlag()),
+			ui.formatItemSize(size),
+			ui.formatColumns(file, size, parentSize),
+			indent)
+		fmt.Fprintf(ui.output, "%s%s\n", prefix, ui.formatItemName(file, ui.getNameWidth(prefix)))
 
-		select {
-		case progress = <-progressChan:
-		case <-doneChan:
-			fmt.Fprint(ui.output, "\r")
-			return
+		if file.IsDir() && ui.shouldExpand(depth) {
+			ui.printItems(file.(*analyze.Dir).Files, size, depth+1)
 		}
+	}
 
-		fmt.Fprintf(ui.output, "\r %s ", string(progressRunes[i]))
+	if hidden > 0 {
+		fmt.Fprintf(ui.output, "%s... and %d more items\n", indent, hidden)
+	}
+
+	// dirs are not printed, but files in them are
+	if ui.filesOnly && ui.shouldExpand(depth) {
+		for _, item := range ui.sortedFiles(items) {
+			if subdir, ok := item.(*analyze.Dir); ok && (ui.showHidden || !isHidden(subdir.GetName())) {
+				ui.printItems(subdir.Files, ui.getSize(subdir), depth+1)
+			}
+		}
+	}
+}
 
-		fmt.Fprint(ui.output, "Scanning... Total items: "+
-			ui.red.Sprint(progress.ItemCount)+
-			" size: "+
-			ui.formatSize(progress.TotalSize))
+// formatColumns returns optional columns printed between size and name of the item
+func (ui *UI) formatColumns(item analyze.Item, size, parentSize int64) string {
+	return ui.formatOtherSize(item) +
+		ui.formatXattrSize(item) +
+		ui.formatOverhead(item) +
+		ui.formatItemCount(item) +
+		ui.formatPercentBar(size, parentSize) +
+		ui.formatRootPercent(size) +
+		ui.formatMtime(item) +
+		ui.formatSparseMarker(item)
+}
 
-		time.Sleep(100 * time.Millisecond)
-		i++
-		i %= 10
+// formatItemName returns name of the item, dirs are marked by "/" (see SetDirMarker).
+// Full (or relative) path without the prefix is returned if showFullPath (or showRelativePath or filesOnly) is set.
+// The name is truncated to given width (0 means unlimited).
+func (ui *UI) formatItemName(item analyze.Item, width int) string {
+	name := item.GetName()
+	if ui.showFullPath {
+		name = item.GetPath()
+	} else if ui.showRelativePath || ui.filesOnly {
+		name = ui.relativePath(item)
+	} else if item.IsDir() {
+		name = ui.markDirName(name)
 	}
+	name = ui.truncateName(name, width)
+
+	if item.IsDir() && ui.colorDir {
+		return ui.blue.Sprint(name)
+	}
+	return name
 }
 
-func (ui *UI) formatSize(size int64) string {
+// markDirName marks the name of dir by the dir marker
+func (ui *UI) markDirName(name string) string {
+	switch ui.dirMarker {
+	case "suffix":
+		return name + "/"
+	case "none":
+		return name
+	default:
+		return "/" + name
+	}
+}
+
+// relativePath returns path of the item with the path of the analyzed dir stripped
+func (ui *UI) relativePath(item analyze.Item) string {
+	path := item.GetPath()
+	if rel, err := filepath.Rel(ui.rootPath, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// formatItemCount returns column with number of items in the dir (empty for files)
+func (ui *UI) formatItemCount(item analyze.Item) string {
+	if !ui.showItemCount {
+		return ""
+	}
+	if !item.IsDir() {
+		return strings.Repeat(" ", ui.itemCountWidth+1)
+	}
+	return padLeft(ui.formatCount(int64(item.GetItemCount())), ui.itemCountWidth) + " "
+}
+
+func (ui *UI) printTotal(dir *analyze.Dir) {
+	size, count := ui.getTotal(dir)
+
+	label := "Total"
+	if ui.countsMatchingOnly() {
+		label = "Total of matching files"
+	}
+	fmt.Fprintf(ui.output,
+		"%s: %s, %s items\n",
+		label,
+		ui.formatSize(size),
+		ui.formatCount(int64(count)))
+}
+
+// getSize returns apparent size or disk usage of the item depending on settings
+func (ui *UI) getSize(item analyze.Item) int64 {
+	if ui.showApparentSize {
+		return item.GetSize()
+	}
+	return item.GetUsage()
+}
+
+// SetOutputFormat sets format used for printing results of analysis.
+// Colors are disabled for all formats except text.
+func (ui *UI) SetOutputFormat(format OutputFormat) {
+	ui.outputFormat = format
+
+	if format != TextOutput {
+		ui.useColors = false
+		color.NoColor = true
+	}
+}
+
+// SetRecursive sets whether output contains all nested items or only the top level
+func (ui *UI) SetRecursive(recursive bool) {
+	ui.recursive = recursive
+}
+
+// SetMaxDepth sets number of printed levels of the directory tree, 0 means only the top level
+// (or the whole tree if recursive output is set)
+func (ui *UI) SetMaxDepth(maxDepth int) {
+	ui.maxDepth = maxDepth
+}
+
+// shouldExpand returns true if directories on given depth (top level is 1) should be printed with their content
+func (ui *UI) shouldExpand(depth int) bool {
 	switch {
-	case size > 1e12:
-		return ui.orange.Sprintf("%.1f", float64(size)/math.Pow(2, 40)) + " TiB"
-	case size > 1e9:
-		return ui.orange.Sprintf("%.1f", float64(size)/math.Pow(2, 30)) + " GiB"
-	case size > 1e6:
-		return ui.orange.Sprintf("%.1f", float64(size)/math.Pow(2, 20)) + " MiB"
-	case size > 1e3:
-		return ui.orange.Sprintf("%.1f", float64(size)/math.Pow(2, 10)) + " KiB"
+	case ui.maxDepth > 0:
+		return depth < ui.maxDepth
 	default:
-		return ui.orange.Sprintf("%d", size) + " B"
+		return ui.recursive
+	}
+}
+
+// SetMaxEntries sets maximal number of printed items, 0 means unlimited
+func (ui *UI) SetMaxEntries(maxEntries int) {
+	ui.maxEntries = maxEntries
+}
+
+// limitFiles returns first maxEntries files and number of the remaining ones
+func (ui *UI) limitFiles(files analyze.Files) (analyze.Files, int) {
+	if ui.maxEntries <= 0 || len(files) <= ui.maxEntries {
+		return files, 0
+	}
+	return files[:ui.maxEntries], len(files) - ui.maxEntries
+}
+
+func (ui *UI) formatSize(size int64) string {
+	if !ui.colorSize {
+		return ui.formatSizeWithColor(size, nil)
+	}
+	return ui.formatSizeWithColor(size, ui.orange)
+}
+
+// formatSizeWithColor formats size with the number highlighted by given color (nil means no color)
+func (ui *UI) formatSizeWithColor(size int64, c *color.Color) string {
+	if ui.rawBytes {
+		return ui.formatCount(size)
+	}
+
+	sprintf := fmt.Sprintf
+	if c != nil {
+		sprintf = c.Sprintf
+	}
+
+	base, units := float64(1<<10), binaryUnits
+	if ui.useSIPrefixes {
+		base, units = 1000, siUnits
+	}
+
+	if ui.forceUnit > UnitB {
+		value := float64(size) / math.Pow(base, float64(ui.forceUnit-UnitB))
+		return sprintf("%.*f", ui.sizePrecision, value) + " " + units[ui.forceUnit-UnitK]
+	}
+	if float64(size) < base || ui.forceUnit == UnitB {
+		return sprintf("%d", size) + " B"
+	}
+
+	// roll over to the next unit when the value would be rounded up to the base
+	value := float64(size) / base
+	unit := 0
+	scale := math.Pow10(ui.sizePrecision)
+	for unit < len(units)-1 && math.Round(value*scale)/scale >= base {
+		value /= base
+		unit++
+	}
+
+	return sprintf("%.*f", ui.sizePrecision, value) + " " + units[unit]
+}
+
+// sizeWidth returns width of the formatted size column
+func (ui *UI) sizeWidth() int {
+	width := 9
+	if ui.sizePrecision > 1 {
+		width = 8 + ui.sizePrecision
+	}
+	return maxInt(width, ui.sizeColumnWidth)
+}
+
+// setSizeColumnWidth widens the size column for sizes up to given maximum
+// when they are all shown in the forced unit (so they can be longer than usual)
+func (ui *UI) setSizeColumnWidth(maxSize int64) {
+	ui.sizeColumnWidth = 0
+	if ui.forceUnit != UnitAuto && !ui.rawBytes {
+		ui.sizeColumnWidth = len(ui.formatSizeWithColor(maxSize, nil))
 	}
 }
 
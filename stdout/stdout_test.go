@@ -2,8 +2,15 @@ package stdout
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dundee/gdu/v4/analyze"
 	"github.com/dundee/gdu/v4/device"
 	"github.com/dundee/gdu/v4/internal/testanalyze"
 	"github.com/dundee/gdu/v4/internal/testdev"
@@ -80,6 +87,131 @@ func TestItemRows(t *testing.T) {
 	assert.Contains(t, output.String(), "KiB")
 }
 
+func TestItemRowsWithMaxEntries(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetMaxEntries(2)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Contains(t, lines[0], "aaa")
+	assert.Contains(t, lines[1], "bbb")
+	assert.Equal(t, "... and 2 more items", lines[2])
+	assert.Equal(t, "Total: 1.0 TiB, 12 items", lines[3])
+}
+
+func TestItemRowsWithMaxEntriesOverCount(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetMaxEntries(10)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "ddd")
+	assert.NotContains(t, output.String(), "more items")
+}
+
+func TestTotal(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	analyzer := &testanalyze.MockedAnalyzer{}
+	ui.analyzer = analyzer
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	dir := analyzer.AnalyzeDir("test_dir", ui.ShouldDirBeIgnored)
+	var usage int64
+	var count int
+	for _, file := range dir.Files {
+		usage += file.GetUsage()
+		count += file.GetItemCount()
+	}
+	assert.Equal(t, dir.GetUsage(), usage)
+	assert.Equal(t, dir.GetItemCount(), count+1) // dir itself is counted too
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Equal(t, "Total: "+ui.formatSize(usage)+", 12 items", lines[len(lines)-1])
+}
+
+func TestTotalWithApparentSize(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "Total: "+ui.formatSize(1<<40+1<<30+1<<20+1<<10+8)+", 12 items\n")
+}
+
+func TestAnalyzePathWithMaxDepth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetMaxDepth(2)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Len(t, lines, 5)
+	assert.Regexp(t, `^ +8\.0 KiB /nested$`, lines[0])
+	assert.Regexp(t, `^ +4\.0 KiB   /subnested$`, lines[1])
+	assert.Regexp(t, `^ +2 B   file2$`, lines[2])
+	assert.Contains(t, lines[3], "Total:")
+	assert.NotContains(t, output.String(), " file\n")
+}
+
+func TestAnalyzePathWithDepthOfWholeTree(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetMaxDepth(3)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Regexp(t, `(?m)^ +5 B     file$`, output.String())
+}
+
+func TestAnalyzePathWithZeroMaxDepth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetMaxDepth(0)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "/nested")
+	assert.NotContains(t, output.String(), "subnested")
+	assert.NotContains(t, output.String(), "file2")
+}
+
+func TestAnalyzePathRecursive(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.Contains(t, output.String(), "   /subnested\n")
+	assert.Contains(t, output.String(), "     file\n")
+}
+
 func TestAnalyzePathWithProgress(t *testing.T) {
 	fin := testdir.CreateTestDir()
 	defer fin()
@@ -123,6 +255,121 @@ func TestShowDevicesWithErr(t *testing.T) {
 	assert.Contains(t, err.Error(), "no such file")
 }
 
+func TestFormatSize(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	assert.Equal(t, "0 B", ui.formatSize(0))
+	assert.Equal(t, "1023 B", ui.formatSize(1023))
+	assert.Equal(t, "1.0 KiB", ui.formatSize(1024))
+	assert.Equal(t, "1.5 KiB", ui.formatSize(1536))
+	assert.Equal(t, "1.0 MiB", ui.formatSize(1048575))
+	assert.Equal(t, "1.0 MiB", ui.formatSize(1048576))
+	assert.Equal(t, "953.7 MiB", ui.formatSize(1e9))
+	assert.Equal(t, "1.0 GiB", ui.formatSize(1<<30-1))
+	assert.Equal(t, "1.0 GiB", ui.formatSize(1<<30))
+	assert.Equal(t, "931.3 GiB", ui.formatSize(1e12))
+	assert.Equal(t, "1.0 TiB", ui.formatSize(1<<40))
+	assert.Equal(t, "1024.0 TiB", ui.formatSize(1<<50))
+}
+
+func TestFormatSizePrecision(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	size := int64(4_546_763_128) // 4.2345 GiB
+
+	for precision, expected := range []string{"4 GiB", "4.2 GiB", "4.23 GiB", "4.235 GiB"} {
+		err := ui.SetSizePrecision(precision)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, ui.formatSize(size))
+		assert.Equal(t, "1023 B", ui.formatSize(1023))
+	}
+
+	ui.SetSizePrecision(0)
+	assert.Equal(t, "1 MiB", ui.formatSize(1<<20-200))
+	assert.Equal(t, "1023 KiB", ui.formatSize(1<<20-600))
+	ui.SetSizePrecision(3)
+	assert.Equal(t, "1023.999 KiB", ui.formatSize(1<<20-1))
+	assert.Equal(t, "1.000 MiB", ui.formatSize(1<<20))
+}
+
+func TestSetSizePrecisionOutOfRange(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	err := ui.SetSizePrecision(4)
+	assert.Equal(t, "size precision must be between 0 and 3: 4", err.Error())
+	err = ui.SetSizePrecision(-1)
+	assert.Equal(t, "size precision must be between 0 and 3: -1", err.Error())
+	assert.Equal(t, "1.0 KiB", ui.formatSize(1024))
+}
+
+func TestItemRowsWithSizePrecision(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetSizePrecision(3)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   1.000 TiB /aaa", lines[0][1:])
+	assert.Equal(t, "   1.001 KiB ddd", lines[3][1:])
+}
+
+func TestShowDevicesWithSizePrecision(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetSizePrecision(2)
+	ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{{Name: "xxx", MountPoint: "/", Size: 1 << 30, Free: 1 << 29}},
+	})
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, " Device       Size       Used       Free Used% Mount point", lines[0])
+	assert.Equal(t, "    xxx   1.00 GiB 512.00 MiB 512.00 MiB   50% /", lines[1])
+}
+
+func TestFormatSizeRollover(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	assert.Equal(t, "1023.9 KiB", ui.formatSize(1<<20-103))
+	assert.Equal(t, "1.0 MiB", ui.formatSize(1<<20-51))
+	assert.Equal(t, "1023.9 MiB", ui.formatSize(1<<30-100*1<<10))
+	assert.Equal(t, "1.0 GiB", ui.formatSize(1<<30-50*1<<10))
+}
+
+func TestFormatSizeSI(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetUseSIPrefix(true)
+
+	assert.Equal(t, "999 B", ui.formatSize(999))
+	assert.Equal(t, "1.5 KB", ui.formatSize(1500))
+	assert.Equal(t, "1.5 MB", ui.formatSize(1_500_000))
+	assert.Equal(t, "2.0 GB", ui.formatSize(2_000_000_000))
+	assert.Equal(t, "1.0 TB", ui.formatSize(999_999_999_999))
+}
+
+func TestFormatSizeBinary(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	assert.Equal(t, "1.5 KiB", ui.formatSize(1500))
+	assert.Equal(t, "1.4 MiB", ui.formatSize(1_500_000))
+	assert.Equal(t, "1.9 GiB", ui.formatSize(2_000_000_000))
+}
+
+func TestShowDevicesSI(t *testing.T) {
+	output := bytes.NewBuffer(make([]byte, 10))
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetUseSIPrefix(true)
+	ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{{Name: "xxx", Size: 2_000_000_000, Free: 1_500_000}},
+	})
+
+	assert.Contains(t, output.String(), "2.0 GB")
+	assert.Contains(t, output.String(), "1.5 MB")
+}
+
 func TestMaxInt(t *testing.T) {
 	assert.Equal(t, 5, maxInt(2, 5))
 	assert.Equal(t, 4, maxInt(4, 2))
@@ -143,3 +390,525 @@ func getDevicesInfoMock() device.DevicesInfoGetter {
 	mock.Devices = []*device.Device{item}
 	return mock
 }
+
+// slowAnalyzer keeps analyzing until all paths start to be ignored
+type slowAnalyzer struct {
+	testanalyze.MockedAnalyzer
+	doneChan     chan struct{}
+	progressChan chan analyze.CurrentProgress
+}
+
+func (a *slowAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnored) *analyze.Dir {
+	for !ignore(path) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return a.MockedAnalyzer.AnalyzeDir(path, ignore)
+}
+
+func (a *slowAnalyzer) GetDoneChan() chan struct{} {
+	return a.doneChan
+}
+
+func (a *slowAnalyzer) GetProgressChan() chan analyze.CurrentProgress {
+	if a.progressChan == nil {
+		return a.MockedAnalyzer.GetProgressChan()
+	}
+	return a.progressChan
+}
+
+func TestAnalyzePathContextCancelled(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, true, false)
+	ui.SetProgressOutput(bytes.NewBuffer(nil))
+	ui.analyzer = &slowAnalyzer{doneChan: make(chan struct{})}
+	ui.pathChecker = testdir.MockedPathChecker
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := ui.AnalyzePathContext(ctx, "test_dir", nil)
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.NotContains(t, output.String(), "Total")
+}
+
+func TestAnalyzePathContextTimeout(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.analyzer = &slowAnalyzer{doneChan: make(chan struct{})}
+	ui.pathChecker = testdir.MockedPathChecker
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := ui.AnalyzePathContext(ctx, "test_dir", nil)
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Empty(t, output.String())
+}
+
+func TestAnalyzePathContextNotCancelled(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+
+	err := ui.AnalyzePathContext(context.Background(), "test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "Total")
+}
+
+func TestAnalyzePathWithTimeout(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetTimeout(50 * time.Millisecond)
+	ui.analyzer = &slowAnalyzer{doneChan: make(chan struct{})}
+	ui.pathChecker = testdir.MockedPathChecker
+
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Contains(t, err.Error(), "timed out after 50ms")
+	assert.NotContains(t, output.String(), "Partial total")
+}
+
+func TestAnalyzePathWithTimeoutAndAnalyzerFactory(t *testing.T) {
+	created := 0
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetTimeout(50 * time.Millisecond)
+	ui.SetAnalyzerFactory(func() analyze.Analyzer {
+		created++
+		return &slowAnalyzer{doneChan: make(chan struct{})}
+	})
+	ui.pathChecker = testdir.MockedPathChecker
+
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 2, created)
+	assert.IsType(t, &slowAnalyzer{}, ui.analyzer)
+}
+
+func TestAnalyzePathWithTimeoutKeepsSetAnalyzer(t *testing.T) {
+	analyzer := &slowAnalyzer{doneChan: make(chan struct{})}
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetTimeout(50 * time.Millisecond)
+	ui.SetAnalyzer(analyzer)
+	ui.pathChecker = testdir.MockedPathChecker
+
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Same(t, analyzer, ui.analyzer)
+}
+
+func TestAnalyzePathWithTimeoutAndProgress(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	progressChan := make(chan analyze.CurrentProgress, 1)
+	progressChan <- analyze.CurrentProgress{ItemCount: 42, TotalSize: 2048}
+
+	progressOutput := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, true, false)
+	ui.SetProgressOutput(progressOutput)
+	ui.SetTimeout(200 * time.Millisecond)
+	ui.analyzer = &slowAnalyzer{doneChan: make(chan struct{}), progressChan: progressChan}
+	ui.pathChecker = testdir.MockedPathChecker
+
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Contains(t, output.String(), "Partial total: 2.0 KiB, 42 items")
+	assert.Contains(t, progressOutput.String(), "Scanning... Total items: 42")
+}
+
+func TestAnalyzePathFinishedBeforeTimeout(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetTimeout(time.Minute)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "Total")
+}
+
+// concurrencyAnalyzer records the concurrency limit set by UI
+type concurrencyAnalyzer struct {
+	testanalyze.MockedAnalyzer
+	maxConcurrency int
+}
+
+func (a *concurrencyAnalyzer) SetMaxConcurrency(n int) {
+	a.maxConcurrency = n
+}
+
+func TestAnalyzePathWithMaxConcurrency(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	analyzer := &concurrencyAnalyzer{}
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetMaxConcurrency(4)
+	ui.analyzer = analyzer
+	ui.pathChecker = testdir.MockedPathChecker
+
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, analyzer.maxConcurrency)
+	assert.Contains(t, output.String(), "Total")
+}
+
+func TestAnalyzePathWithDedupHardlinks(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.Link("test_dir/nested/file2", "test_dir/file3")
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetDedupHardlinks(true)
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, strings.Count(output.String(), "\nH "))
+	assert.Contains(t, output.String(), "0 B")
+}
+
+func TestShowItemCount(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetShowItemCount(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    8.0 KiB 4 /nested", lines[0])
+	assert.Equal(t, "    4.0 KiB 2   /subnested", lines[1])
+	assert.Equal(t, "        5 B       file", lines[2])
+	assert.Equal(t, "        2 B     file2", lines[3])
+	assert.Equal(t, "Total: 12.0 KiB, 5 items", lines[4])
+}
+
+func TestShowItemCountWithMockedAnalyzer(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowItemCount(true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	// 12 items in total, so the column is two chars wide
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   1.0 TiB  5 /aaa", lines[0][1:])
+	assert.Equal(t, "   1.0 GiB  3 /bbb", lines[1][1:])
+	assert.Equal(t, "   1.0 MiB  2 /ccc", lines[2][1:])
+	assert.Equal(t, "   1.0 KiB    ddd", lines[3][1:])
+}
+
+func TestSummarizeOnly(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetSummarizeOnly(true)
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Total: 12.0 KiB, 5 items\n", output.String())
+}
+
+func TestNoTotal(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowTotal(false)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "   1.0 KiB ddd", lines[3][1:])
+	assert.NotContains(t, output.String(), "Total")
+}
+
+func TestShowTotalByDefault(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 5)
+	assert.True(t, strings.HasPrefix(lines[4], "Total: "))
+}
+
+func TestFormatSizeRawBytes(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, true, false, false)
+	ui.SetRawBytes(true)
+
+	assert.Equal(t, "0", ui.formatSize(0))
+	assert.Equal(t, "1023", ui.formatSize(1023))
+	assert.Equal(t, "1024", ui.formatSize(1024))
+	assert.Equal(t, "1048576", ui.formatSize(1<<20))
+	assert.Equal(t, "1000000000", ui.formatSize(1e9))
+	assert.Equal(t, "1099511627776", ui.formatSize(1<<40))
+	assert.Equal(t, "1125899906842624", ui.formatSize(1<<50))
+}
+
+func TestItemRowsWithRawBytes(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetRawBytes(true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    1099511627777 /aaa", lines[0][1:])
+	assert.Equal(t, "       1073741825 /bbb", lines[1][1:])
+	assert.Equal(t, "          1048577 /ccc", lines[2][1:])
+	assert.Equal(t, "             1025 ddd", lines[3][1:])
+	assert.Equal(t, "Total: 1100586419204, 12 items", lines[4])
+}
+
+func TestShowDevicesWithRawBytes(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetRawBytes(true)
+	ui.ListDevices(testdev.DevicesInfoGetterMock{
+		Devices: []*device.Device{{Name: "xxx", MountPoint: "/", Size: 2_000_000_000, Free: 1_500_000}},
+	})
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, " Device             Size             Used             Free Used% Mount point", lines[0])
+	assert.Equal(t, "    xxx       2000000000       1998500000          1500000  100% /", lines[1])
+}
+
+// progressAnalyzer reports progress once before finishing the analysis
+type progressAnalyzer struct {
+	testanalyze.MockedAnalyzer
+	progressChan chan analyze.CurrentProgress
+	doneChan     chan struct{}
+}
+
+func newProgressAnalyzer() *progressAnalyzer {
+	return &progressAnalyzer{
+		progressChan: make(chan analyze.CurrentProgress, 1),
+		doneChan:     make(chan struct{}, 1),
+	}
+}
+
+func (a *progressAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnored) *analyze.Dir {
+	a.progressChan <- analyze.CurrentProgress{CurrentItemName: path, ItemCount: 7, TotalSize: 1024}
+	time.Sleep(20 * time.Millisecond)
+	a.doneChan <- struct{}{}
+	return a.MockedAnalyzer.AnalyzeDir(path, ignore)
+}
+
+func (a *progressAnalyzer) GetProgressChan() chan analyze.CurrentProgress {
+	return a.progressChan
+}
+
+func (a *progressAnalyzer) GetDoneChan() chan struct{} {
+	return a.doneChan
+}
+
+func TestProgressOutput(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+	progressOutput := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, true, false)
+	ui.SetProgressOutput(progressOutput)
+	ui.analyzer = newProgressAnalyzer()
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.Contains(t, progressOutput.String(), "Scanning... Total items: 7 size: 1.0 KiB")
+	assert.NotContains(t, progressOutput.String(), "Total: ")
+	assert.Contains(t, output.String(), "Total: ")
+	assert.NotContains(t, output.String(), "Scanning")
+	assert.NotContains(t, output.String(), "\r")
+}
+
+func TestSaveAndPrintScan(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+	scan := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetScanOutput(scan)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, scan.Bytes())
+
+	loadedOutput := bytes.NewBuffer(nil)
+	ui = CreateStdoutUI(loadedOutput, false, false, true)
+	ui.SetRecursive(true)
+	err = ui.PrintScan(scan)
+	assert.Nil(t, err)
+
+	assert.Equal(t, output.String(), loadedOutput.String())
+	assert.Contains(t, loadedOutput.String(), "subnested")
+}
+
+func TestPrintInvalidScan(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.PrintScan(bytes.NewBufferString("xxx"))
+	assert.Contains(t, err.Error(), "loading scan")
+}
+
+func TestShowFullPath(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetShowFullPath(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	abspath, _ := filepath.Abs("test_dir")
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    8.0 KiB "+abspath+"/nested", lines[0])
+	assert.Equal(t, "    4.0 KiB "+abspath+"/nested/subnested", lines[1])
+	assert.Equal(t, "        5 B "+abspath+"/nested/subnested/file", lines[2])
+	assert.Equal(t, "        2 B "+abspath+"/nested/file2", lines[3])
+}
+
+func TestDirMarker(t *testing.T) {
+	cases := []struct {
+		marker, dirName string
+	}{
+		{"", "/nested"},
+		{"prefix", "/nested"},
+		{"suffix", "nested/"},
+		{"none", "nested"},
+	}
+
+	for _, c := range cases {
+		fin := testdir.CreateTestDir()
+
+		output := bytes.NewBuffer(nil)
+
+		ui := CreateStdoutUI(output, false, false, true)
+		assert.Nil(t, ui.SetDirMarker(c.marker))
+		ui.SetRecursive(true)
+		ui.AnalyzePath("test_dir", nil)
+
+		lines := strings.Split(output.String(), "\n")
+		assert.Equal(t, "    8.0 KiB "+c.dirName, lines[0], c.marker)
+		assert.Equal(t, "        2 B   file2", lines[3], c.marker)
+
+		fin()
+	}
+}
+
+func TestSetUnknownDirMarker(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	err := ui.SetDirMarker("brackets")
+	assert.Equal(t, "unknown dir marker: brackets", err.Error())
+}
+
+func TestShowRelativePath(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetRecursive(true)
+	ui.SetShowRelativePath(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    8.0 KiB nested", lines[0])
+	assert.Equal(t, "    4.0 KiB nested/subnested", lines[1])
+	assert.Equal(t, "        5 B nested/subnested/file", lines[2])
+	assert.Equal(t, "        2 B nested/file2", lines[3])
+}
+
+func TestShowRelativePathOfLargestFiles(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetLargestFiles(2)
+	ui.SetShowRelativePath(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.True(t, strings.HasSuffix(lines[0], " nested/subnested/file"))
+	assert.True(t, strings.HasSuffix(lines[1], " nested/file2"))
+}
+
+func TestSetAnalyzer(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetAnalyzer(&testanalyze.MockedAnalyzer{})
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   1.0 TiB /aaa", lines[0][1:])
+	assert.Equal(t, "   1.0 GiB /bbb", lines[1][1:])
+	assert.Equal(t, "   1.0 MiB /ccc", lines[2][1:])
+	assert.Equal(t, "   1.0 KiB ddd", lines[3][1:])
+}
+
+func TestShowFullPathWithMockedAnalyzer(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetShowFullPath(true)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "   1.0 TiB test_dir/aaa", lines[0][1:])
+	assert.Equal(t, "   1.0 KiB test_dir/ddd", lines[3][1:])
+}
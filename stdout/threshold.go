@@ -0,0 +1,29 @@
+package stdout
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSizeOverThreshold is returned (wrapped) by the analysis
+// when the total size exceeds the size set by SetFailOver
+var ErrSizeOverThreshold = errors.New("size over threshold")
+
+// SetFailOver sets size which the total of the analysis must not exceed (0 means no limit).
+// The results are printed anyway, but ErrSizeOverThreshold is returned afterwards.
+func (ui *UI) SetFailOver(size int64) {
+	ui.failOverSize = size
+}
+
+// checkFailOver returns error if the total size exceeds the threshold set by SetFailOver
+func (ui *UI) checkFailOver(total int64) error {
+	if ui.failOverSize <= 0 || total <= ui.failOverSize {
+		return nil
+	}
+	return fmt.Errorf(
+		"%w: total %s exceeds %s",
+		ErrSizeOverThreshold,
+		ui.formatSizeWithColor(total, nil),
+		ui.formatSizeWithColor(ui.failOverSize, nil),
+	)
+}
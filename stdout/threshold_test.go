@@ -0,0 +1,62 @@
+package stdout
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailOver(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetFailOver(1024)
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.True(t, errors.Is(err, ErrSizeOverThreshold))
+	assert.Equal(t, "size over threshold: total 12.0 KiB exceeds 1.0 KiB", err.Error())
+	assert.Contains(t, output.String(), "Total: 12.0 KiB")
+}
+
+func TestNotFailOver(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, true)
+	ui.SetFailOver(1 << 30)
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+}
+
+func TestFailOverBoundary(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, true)
+
+	assert.Nil(t, ui.checkFailOver(1<<20))
+
+	ui.SetFailOver(1 << 20)
+	assert.Nil(t, ui.checkFailOver(1<<20))
+	assert.True(t, errors.Is(ui.checkFailOver(1<<20+1), ErrSizeOverThreshold))
+}
+
+func TestFailOverOfGrandTotal(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/other", os.ModePerm)
+	os.WriteFile("test_dir/other/file", []byte("xxx"), 0644)
+
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, true)
+	// each of the paths alone is smaller than the threshold
+	ui.SetFailOver(10 * 1024)
+	err := ui.AnalyzePaths([]string{"test_dir/nested", "test_dir/other"})
+
+	assert.True(t, errors.Is(err, ErrSizeOverThreshold))
+}
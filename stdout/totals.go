@@ -0,0 +1,37 @@
+package stdout
+
+import (
+	"fmt"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetChildTotals sets whether only a summary line with the recursive total
+// of each immediate subdirectory should be printed instead of the listing
+func (ui *UI) SetChildTotals(childTotals bool) {
+	ui.childTotals = childTotals
+}
+
+// printChildTotals prints total size and item count of each subdir of the dir
+func (ui *UI) printChildTotals(dir *analyze.Dir) {
+	subdirs := make(analyze.Files, 0, len(dir.Files))
+	for _, item := range dir.Files {
+		if item.IsDir() {
+			subdirs = append(subdirs, item)
+		}
+	}
+
+	subdirs, hidden := ui.selectFiles(subdirs)
+	for _, subdir := range subdirs {
+		size, count := ui.getTotal(subdir.(*analyze.Dir))
+		fmt.Fprintf(ui.output,
+			"%s: %s, %s items\n",
+			subdir.GetName(),
+			ui.formatSize(size),
+			ui.formatCount(int64(count)))
+	}
+
+	if hidden > 0 {
+		fmt.Fprintf(ui.output, "... and %d more dirs\n", hidden)
+	}
+}
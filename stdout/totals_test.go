@@ -0,0 +1,72 @@
+package stdout
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+// getSubtreeTotal returns apparent size and number of all items in the path (including itself)
+func getSubtreeTotal(t *testing.T, path string) (int64, int) {
+	var (
+		size  int64
+		count int
+	)
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		size += info.Size()
+		count++
+		return err
+	})
+	assert.Nil(t, err)
+	return size, count
+}
+
+func TestChildTotals(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.MkdirAll("test_dir/other/deep", os.ModePerm)
+	os.WriteFile("test_dir/other/deep/file", []byte("xxx"), 0644)
+	os.WriteFile("test_dir/file", []byte("not a dir"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetChildTotals(true)
+	ui.SetRawBytes(true)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	for i, name := range []string{"nested", "other"} {
+		size, count := getSubtreeTotal(t, filepath.Join("test_dir", name))
+		assert.Equal(t, fmt.Sprintf("%s: %d, %d items", name, size, count), lines[i])
+	}
+}
+
+func TestChildTotalsWithTop(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetChildTotals(true)
+	ui.SetMaxEntries(2)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Equal(t, []string{
+		"aaa: 1.0 TiB, 5 items",
+		"bbb: 1.0 GiB, 3 items",
+		"... and 1 more dirs",
+	}, lines)
+}
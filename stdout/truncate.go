@@ -0,0 +1,71 @@
+package stdout
+
+import (
+	"fmt"
+
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	ellipsis      = "…"
+	asciiEllipsis = "..."
+)
+
+var truncationModes = []string{"end", "middle"}
+
+// SetNameWidth sets maximal width of printed names (or paths) of items in terminal columns, 0 means unlimited.
+// Longer names are shortened by ellipsis, wide characters (e.g. CJK) are counted as two columns.
+func (ui *UI) SetNameWidth(width int) {
+	ui.nameWidth = width
+}
+
+// SetNameTruncation sets where the ellipsis is placed in too long names ("end" or "middle"),
+// empty mode means the default "end"
+func (ui *UI) SetNameTruncation(mode string) error {
+	if mode == "" {
+		mode = "end"
+	}
+	for _, m := range truncationModes {
+		if mode == m {
+			ui.nameTruncation = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown truncation mode: %s", mode)
+}
+
+// truncateName shortens the name to given width, 0 means unlimited
+func (ui *UI) truncateName(name string, width int) string {
+	if width <= 0 || runewidth.StringWidth(name) <= width {
+		return name
+	}
+
+	tail := ellipsis
+	if ui.asciiOnly {
+		tail = asciiEllipsis
+	}
+	tailWidth := runewidth.StringWidth(tail)
+	if width <= tailWidth {
+		return runewidth.Truncate(name, width, "")
+	}
+
+	if ui.nameTruncation == "middle" {
+		width -= tailWidth
+		endWidth := width / 2
+		return runewidth.Truncate(name, width-endWidth, "") + tail + truncateStart(name, endWidth)
+	}
+	return runewidth.Truncate(name, width, tail)
+}
+
+// truncateStart returns the longest end of the string not wider than given width
+func truncateStart(s string, width int) string {
+	runes := []rune(s)
+	i := len(runes)
+	for w := 0; i > 0; i-- {
+		w += runewidth.RuneWidth(runes[i-1])
+		if w > width {
+			break
+		}
+	}
+	return string(runes[i:])
+}
@@ -0,0 +1,74 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameWidth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/příliš_žluťoučký_kůň.txt", []byte("xxx"), 0644)
+	os.WriteFile("test_dir/日本語のファイル名.txt", []byte("xx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetNameWidth(12)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    8.0 KiB /nested", lines[0])
+	assert.Equal(t, "        3 B příliš_žluť…", lines[1])
+	assert.Equal(t, "        2 B 日本語のフ…", lines[2])
+}
+
+func TestNameWidthMiddle(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetNameWidth(11)
+	assert.Nil(t, ui.SetNameTruncation("middle"))
+
+	assert.Equal(t, "příli…ň.txt", ui.truncateName("příliš_žluťoučký_kůň.txt", ui.nameWidth))
+	assert.Equal(t, "日本….txt", ui.truncateName("日本語のファイル名.txt", ui.nameWidth))
+	assert.Equal(t, "short.txt", ui.truncateName("short.txt", ui.nameWidth))
+}
+
+func TestNameWidthASCIIOnly(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetNameWidth(8)
+	ui.SetASCIIOnly(true)
+
+	assert.Equal(t, "long_...", ui.truncateName("long_file_name", ui.nameWidth))
+
+	ui.SetNameWidth(2)
+	assert.Equal(t, "lo", ui.truncateName("long_file_name", ui.nameWidth))
+}
+
+func TestNameWidthOfLargestFiles(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetNameWidth(10)
+	ui.SetLargestFiles(1)
+	ui.SetShowRelativePath(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "        5 B nested/su…", lines[0])
+}
+
+func TestInvalidNameTruncation(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	err := ui.SetNameTruncation("start")
+	assert.Equal(t, "unknown truncation mode: start", err.Error())
+}
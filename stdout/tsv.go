@@ -0,0 +1,32 @@
+package stdout
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// tsvEscaper escapes characters which would break the tab-separated format
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// printTSV writes one tab-separated row for each top-level item of the dirs (under one header)
+func (ui *UI) printTSV(dirs ...*analyze.Dir) error {
+	fmt.Fprintln(ui.output, "path\tsize\tusage\titems")
+
+	for _, dir := range dirs {
+		files, _ := ui.selectFiles(dir.Files)
+
+		for _, file := range files {
+			fmt.Fprintf(
+				ui.output,
+				"%s\t%d\t%d\t%d\n",
+				tsvEscaper.Replace(file.GetPath()),
+				file.GetSize(),
+				file.GetUsage(),
+				file.GetItemCount(),
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzePathTSV(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, true, false, false)
+	ui.SetOutputFormat(TSVOutput)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	assert.True(t, color.NoColor)
+	assert.NotContains(t, output.String(), "\x1b[")
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 5)
+	assert.Equal(t, []string{"path", "size", "usage", "items"}, strings.Split(lines[0], "\t"))
+	assert.Equal(t, []string{"test_dir/aaa", "1099511627778", "1099511627777", "5"}, strings.Split(lines[1], "\t"))
+	assert.Equal(t, []string{"test_dir/ddd", "1026", "1025", "1"}, strings.Split(lines[4], "\t"))
+}
+
+func TestAnalyzePathTSVEscaping(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/with\ttab", []byte("abc"), 0644)
+	os.WriteFile("test_dir/with\nnewline", []byte("ab"), 0644)
+	os.WriteFile(`test_dir/with\backslash`, []byte("a"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(TSVOutput)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	abspath, _ := filepath.Abs("test_dir")
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 5)
+	for _, line := range lines {
+		assert.Len(t, strings.Split(line, "\t"), 4)
+	}
+
+	assert.Contains(t, output.String(), abspath+`/with\ttab`+"\t3\t")
+	assert.Contains(t, output.String(), abspath+`/with\nnewline`+"\t2\t")
+	assert.Contains(t, output.String(), abspath+`/with\\backslash`+"\t1\t")
+}
@@ -0,0 +1,81 @@
+package stdout
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// maxUnreadableSamples is the number of unreadable dirs listed in the warning
+const maxUnreadableSamples = 5
+
+// SetFailOnReadErrors sets whether the analysis should fail
+// when some dir could not be read (e.g. because of missing permissions)
+func (ui *UI) SetFailOnReadErrors(failOnReadErrors bool) {
+	ui.failOnReadErrors = failOnReadErrors
+}
+
+// getUnreadableDirs returns paths of dirs which could not be read.
+// A file given as the analyzed path is flagged as unreadable too, but it is not reported.
+func getUnreadableDirs(dir *analyze.Dir) []string {
+	if dir.GetFlag() == '!' {
+		if info, err := os.Stat(dir.GetPath()); err == nil && !info.IsDir() {
+			return nil
+		}
+	}
+	return collectUnreadableDirs(dir)
+}
+
+func collectUnreadableDirs(dir *analyze.Dir) []string {
+	var paths []string
+	if dir.GetFlag() == '!' {
+		paths = append(paths, dir.GetPath())
+	}
+
+	for _, item := range dir.Files {
+		if subdir, ok := item.(*analyze.Dir); ok {
+			paths = append(paths, collectUnreadableDirs(subdir)...)
+		}
+	}
+	return paths
+}
+
+func formatUnreadableDirs(paths []string) string {
+	samples := paths
+	if len(samples) > maxUnreadableSamples {
+		samples = samples[:maxUnreadableSamples]
+	}
+
+	res := fmt.Sprintf(
+		"%s could not be read: %s",
+		pluralize(len(paths), "directory", "directories"),
+		strings.Join(samples, ", "),
+	)
+	if len(paths) > len(samples) {
+		res += ", ..."
+	}
+	return res
+}
+
+// checkUnreadableDirs returns error if some dir could not be read and failOnReadErrors is set
+func (ui *UI) checkUnreadableDirs(dir *analyze.Dir) error {
+	if !ui.failOnReadErrors {
+		return nil
+	}
+	if paths := getUnreadableDirs(dir); len(paths) > 0 {
+		return fmt.Errorf("analysis is not complete, %s", formatUnreadableDirs(paths))
+	}
+	return nil
+}
+
+// printUnreadableDirsWarning prints warning with dirs which could not be read to stderr (in text format only)
+func (ui *UI) printUnreadableDirsWarning(dir *analyze.Dir) {
+	if !ui.printsText() {
+		return
+	}
+	if paths := getUnreadableDirs(dir); len(paths) > 0 {
+		fmt.Fprintf(ui.warningOutput, "Warning: %s\n", formatUnreadableDirs(paths))
+	}
+}
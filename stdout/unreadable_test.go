@@ -0,0 +1,102 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+// createUnreadableTestDir strips permissions of the subnested dir
+func createUnreadableTestDir(t *testing.T) func() {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("permissions cannot be stripped on this platform or for root")
+	}
+
+	fin := testdir.CreateTestDir()
+	os.Chmod("test_dir/nested/subnested", 0)
+	return func() {
+		os.Chmod("test_dir/nested/subnested", 0755)
+		fin()
+	}
+}
+
+func TestUnreadableDirsWarning(t *testing.T) {
+	fin := createUnreadableTestDir(t)
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+	warnings := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.warningOutput = warnings
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	subnested, _ := filepath.Abs("test_dir/nested/subnested")
+	assert.Equal(t, "Warning: 1 directory could not be read: "+subnested+"\n", warnings.String())
+	assert.NotContains(t, output.String(), "Warning")
+}
+
+func TestFailOnReadErrors(t *testing.T) {
+	fin := createUnreadableTestDir(t)
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetFailOnReadErrors(true)
+	err := ui.AnalyzePath("test_dir", nil)
+
+	subnested, _ := filepath.Abs("test_dir/nested/subnested")
+	assert.Equal(t, "analysis is not complete, 1 directory could not be read: "+subnested, err.Error())
+	assert.Empty(t, output.String())
+}
+
+func TestNoUnreadableDirs(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetFailOnReadErrors(true)
+	err := ui.AnalyzePath("test_dir", nil)
+
+	assert.Nil(t, err)
+	assert.NotContains(t, output.String(), "Warning")
+}
+
+func TestFileIsNotUnreadableDir(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+	warnings := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetFailOnReadErrors(true)
+	ui.warningOutput = warnings
+	err := ui.AnalyzePath("test_dir/nested/file2", nil)
+
+	assert.Nil(t, err)
+	assert.Empty(t, warnings.String())
+}
+
+func TestFormatUnreadableDirs(t *testing.T) {
+	dir := &analyze.Dir{File: &analyze.File{Name: "root", Flag: '.'}, BasePath: "/"}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		dir.Files = append(dir.Files, &analyze.Dir{File: &analyze.File{Name: name, Flag: '!', Parent: dir}})
+	}
+
+	assert.Equal(
+		t,
+		"6 directories could not be read: /root/a, /root/b, /root/c, /root/d, /root/e, ...",
+		formatUnreadableDirs(getUnreadableDirs(dir)),
+	)
+}
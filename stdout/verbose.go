@@ -0,0 +1,111 @@
+package stdout
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetVerbose sets whether paths skipped during the last analysis should be reported after the results
+// (in text format only): paths ignored by the ignore options and items left out of the listing by the filters
+// (min size, age, extension and name), each with the reason, and retried reads of dirs (see SetReadRetries).
+// Only items of the printed levels of the tree are reported as filtered out.
+func (ui *UI) SetVerbose(verbose bool) {
+	ui.verbose = verbose
+}
+
+// addIgnoredPath records path ignored by ShouldDirBeIgnored during the analysis,
+// it is called concurrently by the analyzer
+func (ui *UI) addIgnoredPath(path string) {
+	ui.skippedMutex.Lock()
+	defer ui.skippedMutex.Unlock()
+	ui.ignoredPaths = append(ui.ignoredPaths, path)
+}
+
+// addRetriedRead records retry of failed read of the dir, it is called concurrently by the analyzer
+func (ui *UI) addRetriedRead(path string, attempt int, err error) {
+	ui.skippedMutex.Lock()
+	defer ui.skippedMutex.Unlock()
+	ui.retriedReads = append(ui.retriedReads, fmt.Sprintf("%s (attempt %d: %s)", path, attempt, err.Error()))
+}
+
+// filteredPath is an item left out by the filters with the reason
+type filteredPath struct {
+	path   string
+	reason string
+}
+
+// getFilterReason returns which filter leaves the item out, empty string if the item passes all of them
+func (ui *UI) getFilterReason(item analyze.Item) string {
+	switch {
+	case ui.getSize(item) < ui.minSize:
+		return "smaller than min size"
+	case !ui.matchesFilters(item, mtimeFilter):
+		return "modification time"
+	case !ui.matchesFilters(item, extensionFilter):
+		return "extension"
+	case !ui.matchesFilters(item, nameFilter):
+		return "name"
+	case !ui.matchesFilters(item, allFilters):
+		return "no file matching all filters"
+	}
+	return ""
+}
+
+// collectFilteredPaths returns items left out of the listing by the filters,
+// the items are walked the same way as they are printed by printItems
+func (ui *UI) collectFilteredPaths(items analyze.Files, depth int, paths []filteredPath) []filteredPath {
+	for _, item := range items {
+		if !ui.showHidden && isHidden(item.GetName()) || ui.dirsOnly && !item.IsDir() {
+			continue
+		}
+
+		subdir, isDir := item.(*analyze.Dir)
+		if ui.filesOnly && isDir {
+			// dirs are not printed, but files in them are
+			if ui.shouldExpand(depth) {
+				paths = ui.collectFilteredPaths(subdir.Files, depth+1, paths)
+			}
+			continue
+		}
+
+		if reason := ui.getFilterReason(item); reason != "" {
+			paths = append(paths, filteredPath{path: item.GetPath(), reason: reason})
+		} else if isDir && ui.shouldExpand(depth) {
+			paths = ui.collectFilteredPaths(subdir.Files, depth+1, paths)
+		}
+	}
+	return paths
+}
+
+// printSkippedPaths prints paths ignored during the last analysis of the dir, files left out by the filters
+// and retried reads
+func (ui *UI) printSkippedPaths(dir *analyze.Dir) {
+	if !ui.verbose || !ui.printsText() {
+		return
+	}
+
+	sort.Strings(ui.ignoredPaths)
+	fmt.Fprintf(ui.output, "Ignored: %s\n", pluralize(len(ui.ignoredPaths), "path", "paths"))
+	for _, path := range ui.ignoredPaths {
+		fmt.Fprintf(ui.output, "  %s\n", path)
+	}
+
+	filtered := ui.collectFilteredPaths(dir.Files, 1, make([]filteredPath, 0))
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].path < filtered[j].path
+	})
+	fmt.Fprintf(ui.output, "Filtered out: %s\n", pluralize(len(filtered), "item", "items"))
+	for _, f := range filtered {
+		fmt.Fprintf(ui.output, "  %s (%s)\n", f.path, f.reason)
+	}
+
+	if ui.readRetries > 0 {
+		sort.Strings(ui.retriedReads)
+		fmt.Fprintf(ui.output, "Retried: %s\n", pluralize(len(ui.retriedReads), "read", "reads"))
+		for _, retry := range ui.retriedReads {
+			fmt.Fprintf(ui.output, "  %s\n", retry)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package stdout
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerbose(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/app.log", []byte("xxxxxxxxxx"), 0644)
+	os.WriteFile("test_dir/nested/big.txt", []byte("xxxxxxxxxx"), 0644)
+	os.Mkdir("test_dir/cache", 0755)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetVerbose(true)
+	ui.SetIgnoreDirPatterns([]string{"*/cache", "*/subnested"})
+	ui.SetExcludeExtensions([]string{"log"})
+	ui.SetMinSize(5)
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	out := output.String()
+	lines := strings.Split(out[strings.Index(out, "Ignored:"):], "\n")
+	assert.Equal(t, "Ignored: 2 paths", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], "/test_dir/cache"))
+	assert.True(t, strings.HasSuffix(lines[2], "/test_dir/nested/subnested"))
+	assert.Equal(t, "Filtered out: 2 items", lines[3])
+	assert.True(t, strings.HasSuffix(lines[4], "/test_dir/app.log (extension)"))
+	assert.True(t, strings.HasSuffix(lines[5], "/test_dir/nested/file2 (smaller than min size)"))
+	assert.Equal(t, "", lines[6])
+}
+
+func TestVerboseReportsFilteredItemsOfPrintedLevels(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/big.bin", make([]byte, 10000), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetVerbose(true)
+	ui.SetMinSize(9000)
+	ui.AnalyzePath("test_dir", nil)
+
+	out := output.String()
+	lines := strings.Split(out[strings.Index(out, "Filtered out:"):], "\n")
+	// the dir is reported, files in it are not as they are not printed anyway
+	assert.Equal(t, "Filtered out: 1 item", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], "/test_dir/nested (smaller than min size)"))
+	assert.Equal(t, "", lines[2])
+}
+
+func TestVerboseWithoutSkippedPaths(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetVerbose(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.True(t, strings.HasSuffix(output.String(), "Ignored: 0 paths\nFiltered out: 0 items\n"))
+}
+
+func TestVerboseInJSON(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetVerbose(true)
+	ui.SetOutputFormat(JSONOutput)
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.NotContains(t, output.String(), "Ignored")
+}
+
+// retryingAnalyzer reports one retried read of each dir within the retry limit
+type retryingAnalyzer struct {
+	testanalyze.MockedAnalyzer
+	retries int
+	retried analyze.ReadRetried
+}
+
+func (a *retryingAnalyzer) SetReadRetries(
+	retries int, backoff time.Duration, retried analyze.ReadRetried, cancel <-chan struct{},
+) {
+	a.retries = retries
+	a.retried = retried
+}
+
+func (a *retryingAnalyzer) AnalyzeDir(path string, ignore analyze.ShouldDirBeIgnored) *analyze.Dir {
+	if a.retries > 0 && a.retried != nil {
+		a.retried("test_dir/bbb", 1, errors.New("timed out"))
+		a.retried("test_dir/aaa", 1, errors.New("interrupted"))
+	}
+	return a.MockedAnalyzer.AnalyzeDir(path, ignore)
+}
+
+func TestVerboseWithReadRetries(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, false)
+	ui.SetVerbose(true)
+	ui.SetReadRetries(2, time.Millisecond)
+	ui.analyzer = &retryingAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	ui.AnalyzePath("test_dir", nil)
+
+	assert.True(t, strings.HasSuffix(output.String(),
+		"Retried: 2 reads\n  test_dir/aaa (attempt 1: interrupted)\n  test_dir/bbb (attempt 1: timed out)\n"))
+}
+
+func TestSetNegativeReadRetries(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	err := ui.SetReadRetries(-1, time.Millisecond)
+
+	assert.Equal(t, "read retries must not be negative: -1", err.Error())
+}
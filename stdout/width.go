@@ -0,0 +1,47 @@
+package stdout
+
+import "github.com/mattn/go-runewidth"
+
+// minFittedNameWidth is the width names are never shortened below when fitting lines to the terminal
+const minFittedNameWidth = 8
+
+// minPercentBarWidth is the width percent bars are never shrunk below on narrow terminals
+const minPercentBarWidth = 3
+
+// SetTerminalWidth sets width of the terminal the listing is fitted to, 0 means the fixed layout.
+// Names which would not fit the line are shortened (see SetNameWidth) and percent bars are shrunk on narrow terminals.
+func (ui *UI) SetTerminalWidth(width int) {
+	ui.terminalWidth = width
+}
+
+// getNameWidth returns width the name printed after given prefix of the line can take, 0 means unlimited
+func (ui *UI) getNameWidth(prefix string) int {
+	if ui.terminalWidth <= 0 {
+		return ui.nameWidth
+	}
+
+	width := ui.terminalWidth - runewidth.StringWidth(colorCodeRe.ReplaceAllString(prefix, ""))
+	if width < minFittedNameWidth {
+		width = minFittedNameWidth
+	}
+	if ui.nameWidth > 0 && ui.nameWidth < width {
+		return ui.nameWidth
+	}
+	return width
+}
+
+// getPercentBarWidth returns width of percent bars, one eighth of the terminal width at most the default one
+func (ui *UI) getPercentBarWidth() int {
+	if ui.terminalWidth <= 0 {
+		return percentBarWidth
+	}
+
+	width := ui.terminalWidth / 8
+	switch {
+	case width > percentBarWidth:
+		return percentBarWidth
+	case width < minPercentBarWidth:
+		return minPercentBarWidth
+	}
+	return width
+}
@@ -0,0 +1,75 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerminalWidth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	os.WriteFile("test_dir/a_file_with_very_long_name.txt", []byte("xxx"), 0644)
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetTerminalWidth(24)
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		"    8.0 KiB /nested",
+		"    4.0 KiB   /subnested",
+		"        5 B     file",
+		"        2 B   file2",
+		"        3 B a_file_with…",
+		"Total: 12.0 KiB, 6 items",
+	}, lines)
+	for _, line := range lines {
+		assert.LessOrEqual(t, runewidth.StringWidth(line), 24)
+	}
+}
+
+func TestTerminalWidthWithNameWidth(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+	ui.SetTerminalWidth(40)
+
+	assert.Equal(t, 28, ui.getNameWidth("    1.0 KiB "))
+	assert.Equal(t, minFittedNameWidth, ui.getNameWidth(strings.Repeat(" ", 39)))
+
+	ui.SetNameWidth(10)
+	assert.Equal(t, 10, ui.getNameWidth("    1.0 KiB "))
+}
+
+func TestTerminalWidthWithPercentBars(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetShowPercentBars(true)
+	ui.SetTerminalWidth(40)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, "    8.0 KiB [###  ]  67% /nested", lines[0])
+}
+
+func TestFixedWidth(t *testing.T) {
+	ui := CreateStdoutUI(&bytes.Buffer{}, false, false, false)
+
+	assert.Equal(t, 0, ui.getNameWidth("    1.0 KiB "))
+	assert.Equal(t, percentBarWidth, ui.getPercentBarWidth())
+
+	ui.SetTerminalWidth(10)
+	assert.Equal(t, minPercentBarWidth, ui.getPercentBarWidth())
+}
@@ -0,0 +1,47 @@
+package stdout
+
+import (
+	"errors"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// SetShowXattrs sets whether size of extended attributes (including ACLs stored in them) should be shown
+// in a column and after the total. Size of a dir includes extended attributes of all items in it.
+// Extended attributes of all items of the analyzed tree are read after the analysis.
+// Error is returned on platforms not supporting extended attributes.
+func (ui *UI) SetShowXattrs(showXattrs bool) error {
+	if showXattrs && !xattrsSupported {
+		return errors.New("extended attributes are not supported on this platform")
+	}
+	ui.showXattrs = showXattrs
+	return nil
+}
+
+// collectXattrSizes reads size of extended attributes of all items in the whole tree (not only of the printed ones,
+// as sizes of dirs include their contents) and returns size of the item.
+// Every item is read once more after the analysis, which can take long on big trees.
+// Items which cannot be read are counted as having no extended attributes.
+func (ui *UI) collectXattrSizes(item analyze.Item) int64 {
+	size, _ := getXattrSize(item.GetPath())
+	if dir, ok := item.(*analyze.Dir); ok {
+		for _, file := range dir.Files {
+			size += ui.collectXattrSizes(file)
+		}
+	}
+	ui.xattrSizes[item] = size
+	return size
+}
+
+// formatXattrSize returns column with size of extended attributes of the item
+func (ui *UI) formatXattrSize(item analyze.Item) string {
+	if !ui.showXattrs {
+		return ""
+	}
+
+	width := ui.sizeWidth()
+	if ui.rawBytes {
+		width = ui.rawBytesWidth()
+	}
+	return padLeft(ui.formatItemSize(ui.xattrSizes[item]), width) + " "
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd && !netbsd
+// +build !linux,!darwin,!freebsd,!netbsd
+
+package stdout
+
+import "errors"
+
+const xattrsSupported = false
+
+func getXattrSize(path string) (int64, error) {
+	return 0, errors.New("extended attributes are not supported")
+}
@@ -0,0 +1,39 @@
+//go:build linux || darwin || freebsd || netbsd
+// +build linux darwin freebsd netbsd
+
+package stdout
+
+import (
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const xattrsSupported = true
+
+// getXattrSize returns total size of names and values of extended attributes of the file, symlinks are not followed
+func getXattrSize(path string) (int64, error) {
+	size, err := unix.Llistxattr(path, nil)
+	if err != nil || size == 0 {
+		return 0, err
+	}
+
+	names := make([]byte, size)
+	size, err = unix.Llistxattr(path, names)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, name := range strings.Split(string(names[:size]), "\x00") {
+		if name == "" {
+			continue
+		}
+		valueSize, err := unix.Lgetxattr(path, name, nil)
+		if err != nil {
+			return total, err
+		}
+		total += int64(len(name) + valueSize)
+	}
+	return total, nil
+}
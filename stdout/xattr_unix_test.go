@@ -0,0 +1,61 @@
+//go:build linux || darwin || freebsd || netbsd
+// +build linux darwin freebsd netbsd
+
+package stdout
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func setXattr(t *testing.T, path, name, value string) {
+	if err := unix.Setxattr(path, name, []byte(value), 0); err != nil {
+		t.Skipf("extended attributes are not supported: %s", err)
+	}
+}
+
+func TestGetXattrSize(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	setXattr(t, "test_dir/nested/file2", "user.checksum", "0123456789")
+	setXattr(t, "test_dir/nested/file2", "user.tag", "abc")
+
+	size, err := getXattrSize("test_dir/nested/file2")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len("user.checksum")+10+len("user.tag")+3), size)
+
+	size, err = getXattrSize("test_dir/nested/subnested/file")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), size)
+}
+
+func TestShowXattrs(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	setXattr(t, "test_dir/nested/file2", "user.checksum", "0123456789")
+	setXattr(t, "test_dir/nested/subnested", "user.tag", "abcdefghijklmnopqrstuvwxyz")
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	assert.Nil(t, ui.SetShowXattrs(true))
+	ui.SetRecursive(true)
+	ui.AnalyzePath("test_dir", nil)
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		"    8.0 KiB      57 B /nested",
+		"    4.0 KiB      34 B   /subnested",
+		"        5 B       0 B     file",
+		"        2 B      23 B   file2",
+		"Total: 12.0 KiB, 5 items",
+		"Extended attributes: 57 B",
+	}, lines)
+}
@@ -0,0 +1,56 @@
+package stdout
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/dundee/gdu/v4/analyze"
+)
+
+// xmlItem is serialized as <dir> or <file> element depending on the type of the item
+type xmlItem struct {
+	XMLName   xml.Name
+	Name      string     `xml:"name,attr"`
+	Size      int64      `xml:"size,attr"`
+	Usage     int64      `xml:"usage,attr"`
+	ItemCount int        `xml:"itemCount,attr,omitempty"`
+	Children  []*xmlItem `xml:",any"`
+}
+
+func (ui *UI) printXML(dir *analyze.Dir) error {
+	fmt.Fprint(ui.output, xml.Header)
+
+	encoder := xml.NewEncoder(ui.output)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(ui.createXMLItem(dir, 0)); err != nil {
+		return err
+	}
+	fmt.Fprintln(ui.output)
+	return nil
+}
+
+func (ui *UI) createXMLItem(item analyze.Item, depth int) *xmlItem {
+	res := &xmlItem{
+		XMLName: xml.Name{Local: "file"},
+		Name:    item.GetName(),
+		Size:    item.GetSize(),
+		Usage:   item.GetUsage(),
+	}
+
+	dir, ok := item.(*analyze.Dir)
+	if !ok {
+		return res
+	}
+
+	res.XMLName.Local = "dir"
+	res.ItemCount = dir.GetItemCount()
+	if depth > 0 && !ui.shouldExpand(depth) {
+		return res
+	}
+
+	res.Children = make([]*xmlItem, 0, len(dir.Files))
+	for _, file := range ui.sortedFiles(ui.filterFiles(dir.Files)) {
+		res.Children = append(res.Children, ui.createXMLItem(file, depth+1))
+	}
+	return res
+}
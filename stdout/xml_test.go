@@ -0,0 +1,110 @@
+package stdout
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/dundee/gdu/v4/analyze"
+	"github.com/dundee/gdu/v4/internal/testanalyze"
+	"github.com/dundee/gdu/v4/internal/testdir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzePathXML(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, true, false)
+	ui.SetOutputFormat(XMLOutput)
+	ui.analyzer = &testanalyze.MockedAnalyzer{}
+	ui.pathChecker = testdir.MockedPathChecker
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	assert.True(t, strings.HasPrefix(output.String(), xml.Header))
+	assert.Contains(t, output.String(), `<file name="ddd" size="1026" usage="1025"></file>`)
+
+	var root xmlItem
+	err = xml.Unmarshal(output.Bytes(), &root)
+	assert.Nil(t, err)
+
+	source := (&testanalyze.MockedAnalyzer{}).AnalyzeDir("test_dir", nil)
+	assertXMLItemEqual(t, source, &root)
+}
+
+func TestAnalyzePathXMLRecursive(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(XMLOutput)
+	ui.SetRecursive(true)
+	err := ui.AnalyzePath("test_dir", nil)
+	assert.Nil(t, err)
+
+	var root xmlItem
+	err = xml.Unmarshal(output.Bytes(), &root)
+	assert.Nil(t, err)
+
+	source := analyze.CreateAnalyzer().AnalyzeDir("test_dir", func(_ string) bool { return false })
+	assertXMLItemEqual(t, source, &root)
+
+	subnested := root.Children[0].Children[0]
+	assert.Equal(t, "subnested", subnested.Name)
+	assert.Equal(t, "file", subnested.Children[0].XMLName.Local)
+	assert.Equal(t, int64(5), subnested.Children[0].Size)
+}
+
+func TestAnalyzePathXMLWithMaxDepth(t *testing.T) {
+	fin := testdir.CreateTestDir()
+	defer fin()
+
+	output := bytes.NewBuffer(nil)
+
+	ui := CreateStdoutUI(output, false, false, true)
+	ui.SetOutputFormat(XMLOutput)
+	ui.SetRecursive(true)
+	ui.SetMaxDepth(2)
+	ui.AnalyzePath("test_dir", nil)
+
+	var root xmlItem
+	err := xml.Unmarshal(output.Bytes(), &root)
+	assert.Nil(t, err)
+
+	nested := root.Children[0]
+	assert.Len(t, nested.Children, 2)
+	assert.Equal(t, "subnested", nested.Children[0].Name)
+	assert.Equal(t, "dir", nested.Children[0].XMLName.Local)
+	assert.Empty(t, nested.Children[0].Children)
+}
+
+// assertXMLItemEqual compares unmarshaled XML item with the source item,
+// children are compared by name as they are sorted in the output
+func assertXMLItemEqual(t *testing.T, item analyze.Item, xmlItem *xmlItem) {
+	assert.Equal(t, item.GetName(), xmlItem.Name)
+	assert.Equal(t, item.GetSize(), xmlItem.Size)
+	assert.Equal(t, item.GetUsage(), xmlItem.Usage)
+
+	dir, ok := item.(*analyze.Dir)
+	if !ok {
+		assert.Equal(t, "file", xmlItem.XMLName.Local)
+		return
+	}
+
+	assert.Equal(t, "dir", xmlItem.XMLName.Local)
+	assert.Equal(t, dir.ItemCount, xmlItem.ItemCount)
+	if len(xmlItem.Children) == 0 {
+		return
+	}
+
+	assert.Len(t, xmlItem.Children, len(dir.Files))
+	for _, child := range xmlItem.Children {
+		i, ok := dir.Files.FindByName(child.Name)
+		if assert.True(t, ok) {
+			assertXMLItemEqual(t, dir.Files[i], child)
+		}
+	}
+}
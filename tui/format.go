@@ -20,9 +20,9 @@ func (ui *UI) formatFileRow(item analyze.Item) string {
 	var part int
 
 	if ui.showApparentSize {
-		part = int(float64(item.GetSize()) / float64(item.GetParent().GetSize()) * 10.0)
+		part = getPart(item.GetSize(), item.GetParent().GetSize())
 	} else {
-		part = int(float64(item.GetUsage()) / float64(item.GetParent().GetUsage()) * 10.0)
+		part = getPart(item.GetUsage(), item.GetParent().GetUsage())
 	}
 
 	row := string(item.GetFlag())
@@ -3,6 +3,7 @@ package tui
 import (
 	"testing"
 
+	"github.com/dundee/gdu/v4/device"
 	"github.com/dundee/gdu/v4/internal/testapp"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,3 +20,8 @@ func TestFormatSize(t *testing.T) {
 	assert.Equal(t, "1.0[white:black:-] PiB", ui.formatSize(1<<50, false, false))
 	assert.Equal(t, "1.0[white:black:-] EiB", ui.formatSize(1<<60, false, false))
 }
+
+func TestGetDeviceUsagePart(t *testing.T) {
+	assert.Equal(t, "[#####     ]", getDeviceUsagePart(&device.Device{Size: 100, Free: 50}))
+	assert.Equal(t, "[          ]", getDeviceUsagePart(&device.Device{Size: 0, Free: 0}))
+}
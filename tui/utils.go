@@ -5,7 +5,7 @@ import (
 )
 
 func getDeviceUsagePart(item *device.Device) string {
-	part := int(float64(item.Size-item.Free) / float64(item.Size) * 10.0)
+	part := getPart(item.Size-item.Free, item.Size)
 	row := "["
 	for i := 0; i < 10; i++ {
 		if part > i {
@@ -18,6 +18,14 @@ func getDeviceUsagePart(item *device.Device) string {
 	return row
 }
 
+// getPart returns number of tenths the size takes from the total (0 for zero total)
+func getPart(size, total int64) int {
+	if total <= 0 {
+		return 0
+	}
+	return int(float64(size) / float64(total) * 10.0)
+}
+
 func getUsageGraph(part int) string {
 	graph := " ["
 	for i := 0; i < 10; i++ {